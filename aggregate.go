@@ -0,0 +1,350 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+var ErrInvalidInterval = errors.New("interval must be at least one millisecond")
+
+// Aggregate runs pipeline on c and decodes the results into R, since the
+// output of an aggregation rarely has the shape of the documents.
+func Aggregate[T, R any](ctx context.Context, c *Collection[T], pipeline mongo.Pipeline, opts ...*options.AggregateOptions) (_ []R, err error) {
+	ctx, o := c.begin(ctx, "Aggregate")
+	defer func() { o.end(err) }()
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.AggregateOptions{options.Aggregate().SetComment(comment)}, opts...)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("documents aggregate")
+
+	start := time.Now()
+	cur, err := c.Inner.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	docs, err := decodeAll[R](ctx, cur, c.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	o.count(len(docs))
+
+	took := time.Since(start)
+	c.debug(ctx).Int("count", len(docs)).Dur("took", took).Msg("documents aggregated")
+
+	c.slowAggregate(pipeline, took)
+
+	return docs, nil
+}
+
+// StreamAggregate runs pipeline on c and calls send for each result as it is
+// decoded, e.g. to forward them on a gRPC server stream with bounded memory.
+// Query and decode errors are returned as gRPC status errors, errors from
+// send as they are. The stream is bounded only by ctx.
+func StreamAggregate[T, R any](ctx context.Context, c *Collection[T], pipeline mongo.Pipeline, send func(R) error) (err error) {
+	ctx, o := c.begin(ctx, "StreamAggregate")
+	defer func() { o.end(err) }()
+
+	var opts []*options.AggregateOptions
+	if comment, ok := c.comment(ctx); ok {
+		opts = append(opts, options.Aggregate().SetComment(comment))
+	}
+
+	c.debug(ctx).Msg("documents aggregate stream")
+
+	start := time.Now()
+	cur, err := c.Inner.Aggregate(c.withSession(ctx), pipeline, opts...)
+	if err != nil {
+		return normalizeError(fmt.Errorf(ErrMsgQuery, err))
+	}
+	defer cur.Close(context.Background())
+
+	n := 0
+	for ; cur.Next(ctx); n++ {
+		var doc R
+		if err = cur.Decode(&doc); err != nil {
+			return normalizeError(fmt.Errorf(ErrMsgDecode, err))
+		}
+		if err = send(doc); err != nil {
+			return err
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return normalizeError(fmt.Errorf(ErrMsgQuery, err))
+	}
+
+	c.debug(ctx).Int("count", n).Dur("took", time.Since(start)).Msg("documents aggregate streamed")
+
+	return nil
+}
+
+// CountByTimeBucket counts documents per interval of dateField. Buckets are
+// aligned to the Unix epoch in UTC, so a 24h interval yields calendar days
+// in UTC and works on servers without $dateTrunc.
+func (c *Collection[T]) CountByTimeBucket(ctx context.Context, dateField string, interval time.Duration, filter any) (_ map[time.Time]uint64, err error) {
+	ctx, o := c.begin(ctx, "CountByTimeBucket")
+	defer func() { o.end(err) }()
+
+	ms := interval.Milliseconds()
+	if ms <= 0 {
+		return nil, ErrInvalidInterval
+	}
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	millis := bson.M{"$toLong": "$" + dateField}
+
+	cur, err := c.Inner.Aggregate(ctx, NewPipeline().
+		Match(filter).
+		Match(bson.M{dateField: bson.M{"$type": "date"}}).
+		Group(bson.M{
+			"_id": bson.M{"$toDate": bson.M{"$subtract": bson.A{millis, bson.M{"$mod": bson.A{millis, ms}}}}},
+			"n":   bson.M{"$sum": 1},
+		}).
+		Build(), c.aggregateOptions(ctx, nil)...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	buckets, err := DecodeAll[struct {
+		Start time.Time `bson:"_id"`
+		N     int64     `bson:"n"`
+	}](ctx, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[time.Time]uint64, len(buckets))
+	for _, b := range buckets {
+		result[b.Start.UTC()] = uint64(b.N)
+	}
+	return result, nil
+}
+
+// AggregateAndUpdate runs pipeline and writes updateFn(result) back to the
+// document with the result's _id in one unordered bulk write. Results for
+// which updateFn returns nil are skipped. It returns the modified count.
+func (c *Collection[T]) AggregateAndUpdate(ctx context.Context, pipeline mongo.Pipeline, updateFn func(T) bson.M) (_ uint64, err error) {
+	ctx, o := c.begin(ctx, "AggregateAndUpdate")
+	defer func() { o.end(err) }()
+
+	var opts []*options.AggregateOptions
+	if comment, ok := c.comment(ctx); ok {
+		opts = append(opts, options.Aggregate().SetComment(comment))
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	cur, err := c.Inner.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	var models []mongo.WriteModel
+	for cur.Next(ctx) {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return 0, fmt.Errorf(ErrMsgDecode, err)
+		}
+		if update := updateFn(doc); update != nil {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": cur.Current.Lookup("_id")}).
+				SetUpdate(update))
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	c.debug(ctx).Int("count", len(models)).Msg("documents update")
+
+	start := time.Now()
+	result, err := c.Inner.BulkWrite(ctx, models, c.bulkWriteOptions(ctx, []*options.BulkWriteOptions{options.BulkWrite().SetOrdered(false)})...)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(int(result.ModifiedCount))
+
+	c.debug(ctx).Int64("count", result.ModifiedCount).Dur("took", time.Since(start)).Msg("documents updated")
+
+	return uint64(result.ModifiedCount), nil
+}
+
+// FindComputed is Find with extra fields computed by $addFields, for T
+// types that carry a derived field next to the stored ones.
+func (c *Collection[T]) FindComputed(ctx context.Context, filter any, addFields bson.M, opts ...*options.AggregateOptions) ([]T, error) {
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate[T, T](ctx, c, NewPipeline().Match(filter).AddFields(addFields).Build(), opts...)
+}
+
+// TopNPerGroup returns, for every distinct value of groupField, the n
+// documents with the highest sortField. It uses $push and $slice rather than
+// $topN so it also runs on servers older than 5.2. The result is keyed by
+// the group value as groupKey renders it, e.g. "42", "true" or "null" for
+// documents lacking groupField; values rendering alike share a key.
+func (c *Collection[T]) TopNPerGroup(ctx context.Context, groupField, sortField string, n int, filter any) (_ map[string][]T, err error) {
+	ctx, o := c.begin(ctx, "TopNPerGroup")
+	defer func() { o.end(err) }()
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	cur, err := c.Inner.Aggregate(ctx, NewPipeline().
+		Match(filter).
+		Sort(bson.D{{Key: sortField, Value: -1}}).
+		Group(bson.M{"_id": "$" + groupField, "docs": bson.M{"$push": "$$ROOT"}}).
+		Project(bson.M{"docs": bson.M{"$slice": bson.A{"$docs", n}}}).
+		Build(), c.aggregateOptions(ctx, nil)...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	result := map[string][]T{}
+	for cur.Next(ctx) {
+		var group struct {
+			Docs []T `bson:"docs"`
+		}
+		if err = cur.Decode(&group); err != nil {
+			return nil, fmt.Errorf(ErrMsgDecode, err)
+		}
+		result[groupKey(cur.Current.Lookup("_id"))] = group.Docs
+	}
+	if err = cur.Err(); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	return result, nil
+}
+
+// groupKey renders a group value as a map key: ObjectIDs as hex, strings as
+// they are, numbers and booleans as fmt.Sprint prints them, null as "null"
+// and anything else, e.g. a date or a document, as extended JSON.
+func groupKey(v bson.RawValue) string {
+	switch v.Type {
+	case bson.TypeNull, bson.TypeUndefined:
+		return "null"
+	case bson.TypeInt32, bson.TypeInt64, bson.TypeDouble, bson.TypeDecimal128, bson.TypeBoolean:
+		var value any
+		if err := v.Unmarshal(&value); err == nil {
+			return fmt.Sprint(value)
+		}
+	}
+	return rawIDString(v)
+}
+
+// FindSchemaMismatches samples up to sample documents and returns those
+// that don't round-trip through T: documents that fail to decode, carry
+// fields T doesn't know, or lack fields T always writes.
+func (c *Collection[T]) FindSchemaMismatches(ctx context.Context, sample int) (_ []bson.M, err error) {
+	ctx, o := c.begin(ctx, "FindSchemaMismatches")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	cur, err := c.Inner.Aggregate(ctx, NewPipeline().Stage("$sample", bson.M{"size": sample}).Build(), c.aggregateOptions(ctx, nil)...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	var mismatches []bson.M
+	for cur.Next(ctx) {
+		var stored bson.M
+		if err = cur.Decode(&stored); err != nil {
+			return nil, fmt.Errorf(ErrMsgDecode, err)
+		}
+
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			mismatches = append(mismatches, stored)
+			continue
+		}
+
+		fields, err := ToBson(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !sameKeys(stored, fields) {
+			mismatches = append(mismatches, stored)
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	return mismatches, nil
+}
+
+func sameKeys(a, b bson.M) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// PreparedPipeline is an aggregation run many times with a different
+// leading $match, e.g. a hot parameterized report. The rest of the pipeline
+// is built once and shared between runs.
+type PreparedPipeline[T, R any] struct {
+	c      *Collection[T]
+	stages mongo.Pipeline
+	opts   []*options.AggregateOptions
+}
+
+func NewPreparedPipeline[T, R any](c *Collection[T], stages mongo.Pipeline, opts ...*options.AggregateOptions) *PreparedPipeline[T, R] {
+	return &PreparedPipeline[T, R]{c: c, stages: stages, opts: opts}
+}
+
+// Run executes the pipeline behind a $match on params, which accepts the
+// same filters as Find.
+func (p *PreparedPipeline[T, R]) Run(ctx context.Context, params any) ([]R, error) {
+	filter, err := NormalizeFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := make(mongo.Pipeline, 0, len(p.stages)+1)
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	pipeline = append(pipeline, p.stages...)
+
+	return Aggregate[T, R](ctx, p.c, pipeline, p.opts...)
+}
@@ -0,0 +1,97 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+	"testing"
+)
+
+func TestAggregateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t, WithBypassValidation(), WithComment(func(context.Context) string { return "req-1" }))
+
+	for _, n := range []int{1, 2, 3} {
+		if _, err := c.InsertOne(ctx, bson.M{"n": n}); err != nil {
+			t.Fatalf("InsertOne() error = %v", err)
+		}
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"n": bson.M{"$gte": 2}}}}}
+	modified, err := c.AggregateAndUpdate(ctx, pipeline, func(doc bson.M) bson.M {
+		return bson.M{"$set": bson.M{"big": true}}
+	})
+	if err != nil {
+		t.Fatalf("AggregateAndUpdate() error = %v", err)
+	}
+	if modified != 2 {
+		t.Errorf("AggregateAndUpdate() = %d, want 2", modified)
+	}
+
+	if n, err := c.Count(ctx, bson.M{"big": true}); err != nil || n != 2 {
+		t.Errorf("Count() = %d, %v, want 2", n, err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	insertAll(t, c, bson.M{"g": "a", "n": 1}, bson.M{"g": "a", "n": 2}, bson.M{"g": "b", "n": 5})
+
+	type total struct {
+		Group string `bson:"_id"`
+		Sum   int    `bson:"sum"`
+	}
+	pipeline := NewPipeline().
+		Group(bson.M{"_id": "$g", "sum": bson.M{"$sum": "$n"}}).
+		Sort(bson.D{{Key: "_id", Value: 1}}).
+		Build()
+
+	totals, err := Aggregate[bson.M, total](ctx, c, pipeline)
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if want := []total{{"a", 3}, {"b", 5}}; !reflect.DeepEqual(totals, want) {
+		t.Errorf("Aggregate() = %+v, want %+v", totals, want)
+	}
+}
+
+func TestAggregateMaxResults(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t, WithMaxResults(1))
+	insertAll(t, c, bson.M{"n": 1}, bson.M{"n": 2})
+
+	if _, err := Aggregate[bson.M, bson.M](ctx, c, NewPipeline().Sort(Asc("n")).Build()); !errors.Is(err, ErrTooManyResults) {
+		t.Errorf("Aggregate() error = %v, want ErrTooManyResults", err)
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{oid, oid.Hex()},
+		{"books", "books"},
+		{int32(42), "42"},
+		{int64(-7), "-7"},
+		{2.5, "2.5"},
+		{true, "true"},
+		{primitive.Null{}, "null"},
+		{bson.M{"a": 1}, `{"a": {"$numberInt":"1"}}`},
+	}
+	for _, tt := range tests {
+		typ, data, err := bson.MarshalValue(tt.value)
+		if err != nil {
+			t.Fatalf("MarshalValue(%v) error = %v", tt.value, err)
+		}
+		if got := groupKey(bson.RawValue{Type: typ, Value: data}); got != tt.want {
+			t.Errorf("groupKey(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+var (
+	ErrNoKeyField       = errors.New("document has no key field")
+	ErrNoVersionField   = errors.New("document has no version field")
+	ErrInvalidBatchSize = errors.New("batch size must be positive")
+)
+
+func (c *Collection[T]) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (_ *mongo.BulkWriteResult, err error) {
+	ctx, o := c.begin(ctx, "BulkWrite")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Int("count", len(models)).Msg("documents bulk write")
+
+	start := time.Now()
+	result, err := c.Inner.BulkWrite(ctx, models, c.bulkWriteOptions(ctx, opts)...)
+	if err != nil {
+		return result, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Int64("inserted", result.InsertedCount).Int64("modified", result.ModifiedCount).Int64("upserted", result.UpsertedCount).Int64("deleted", result.DeletedCount).Dur("took", time.Since(start)).Msg("documents bulk written")
+
+	return result, nil
+}
+
+func (c *Collection[T]) bulkWriteOptions(ctx context.Context, opts []*options.BulkWriteOptions) []*options.BulkWriteOptions {
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.BulkWriteOptions{options.BulkWrite().SetComment(comment)}, opts...)
+	}
+	if c.BypassValidation {
+		return append([]*options.BulkWriteOptions{options.BulkWrite().SetBypassDocumentValidation(true)}, opts...)
+	}
+	return opts
+}
+
+// InsertOneModel builds a bulk insert of doc, leaving a zero _id to the
+// server like InsertOne does.
+func InsertOneModel(doc any) (*mongo.InsertOneModel, error) {
+	data, err := omitNilID(doc)
+	if err != nil {
+		return nil, err
+	}
+	return mongo.NewInsertOneModel().SetDocument(data), nil
+}
+
+// UpdateOneModel builds a bulk update accepting the same filters as
+// UpdateOne.
+func UpdateOneModel(filter any, update any) (*mongo.UpdateOneModel, error) {
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update), nil
+}
+
+// ReplaceOneModel builds a bulk replace accepting the same filters as
+// ReplaceOne.
+func ReplaceOneModel(filter any, replacement any) (*mongo.ReplaceOneModel, error) {
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	data, err := omitNilID(replacement)
+	if err != nil {
+		return nil, err
+	}
+	return mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(data), nil
+}
+
+// DeleteOneModel builds a bulk delete accepting the same filters as
+// DeleteOne.
+func DeleteOneModel(filter any) (*mongo.DeleteOneModel, error) {
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return mongo.NewDeleteOneModel().SetFilter(filter), nil
+}
+
+// UpsertStream upserts documents from in, matched on keyField, in unordered
+// bulk writes of up to batchSize documents, flushing a partial batch every
+// flushInterval. Failures are sent on the returned channel, which the caller
+// must drain; it is closed once in is closed and flushed or ctx is done.
+func (c *Collection[T]) UpsertStream(ctx context.Context, in <-chan T, keyField string, batchSize int, flushInterval time.Duration) <-chan error {
+	errs := make(chan error, 1)
+
+	if batchSize <= 0 {
+		errs <- ErrInvalidBatchSize
+		close(errs)
+		return errs
+	}
+
+	go func() {
+		defer close(errs)
+
+		var tick <-chan time.Time
+		if flushInterval > 0 {
+			ticker := time.NewTicker(flushInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		report := func(err error) {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+		}
+
+		batch := make([]mongo.WriteModel, 0, batchSize)
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			if err := c.upsertBatch(ctx, batch); err != nil {
+				report(err)
+			}
+			batch = batch[:0]
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				flush()
+			case doc, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				model, err := upsertModel(doc, keyField)
+				if err != nil {
+					report(err)
+					continue
+				}
+				if batch = append(batch, model); len(batch) >= batchSize {
+					flush()
+				}
+			}
+		}
+	}()
+
+	return errs
+}
+
+func (c *Collection[T]) upsertBatch(ctx context.Context, models []mongo.WriteModel) error {
+	_, err := c.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func upsertModel(doc any, keyField string) (mongo.WriteModel, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgMarshal, err)
+	}
+
+	key, err := bson.Raw(data).LookupErr(keyField)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoKeyField, keyField)
+	}
+
+	replacement, err := omitNilID(bson.Raw(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return mongo.NewReplaceOneModel().
+		SetFilter(bson.M{keyField: key}).
+		SetReplacement(replacement).
+		SetUpsert(true), nil
+}
+
+// BulkUpsertIfNewer upserts docs matched on keyField, overwriting stored
+// documents only while their versionField is lower than the incoming one.
+// A stored document that is as new or newer makes the upsert collide with it
+// on insert; those duplicate key errors are expected and dropped, which is
+// why keyField must have a unique index.
+func (c *Collection[T]) BulkUpsertIfNewer(ctx context.Context, docs []T, keyField, versionField string) (_ *mongo.BulkWriteResult, err error) {
+	ctx, o := c.begin(ctx, "BulkUpsertIfNewer")
+	defer func() { o.end(err) }()
+
+	if len(docs) == 0 {
+		return &mongo.BulkWriteResult{}, nil
+	}
+
+	models := make([]mongo.WriteModel, len(docs))
+	for i, doc := range docs {
+		model, err := upsertIfNewerModel(doc, keyField, versionField)
+		if err != nil {
+			return nil, err
+		}
+		models[i] = model
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Int("count", len(models)).Msg("documents upsert if newer")
+
+	start := time.Now()
+	opts := c.bulkWriteOptions(ctx, []*options.BulkWriteOptions{options.BulkWrite().SetOrdered(false)})
+	result, err := c.Inner.BulkWrite(ctx, models, opts...)
+	if err != nil && !onlyDuplicateKeys(err) {
+		return result, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Int64("upserted", result.UpsertedCount).Int64("modified", result.ModifiedCount).Dur("took", time.Since(start)).Msg("documents upserted if newer")
+
+	return result, nil
+}
+
+func upsertIfNewerModel(doc any, keyField, versionField string) (mongo.WriteModel, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgMarshal, err)
+	}
+
+	key, err := bson.Raw(data).LookupErr(keyField)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoKeyField, keyField)
+	}
+	version, err := bson.Raw(data).LookupErr(versionField)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoVersionField, versionField)
+	}
+
+	elems, err := bson.Raw(data).Elements()
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgUnmarshal, err)
+	}
+
+	var set, setOnInsert bson.D
+	for _, e := range elems {
+		switch e.Key() {
+		case keyField:
+		case "_id":
+			// _id is immutable, so it can only be set when inserting
+			if oid, ok := e.Value().ObjectIDOK(); !ok || !oid.IsZero() {
+				setOnInsert = append(setOnInsert, bson.E{Key: "_id", Value: e.Value()})
+			}
+		default:
+			set = append(set, bson.E{Key: e.Key(), Value: e.Value()})
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+
+	return mongo.NewUpdateOneModel().
+		SetFilter(bson.M{keyField: key, versionField: bson.M{"$lt": version}}).
+		SetUpdate(update).
+		SetUpsert(true), nil
+}
+
+func onlyDuplicateKeys(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+	for _, e := range bwe.WriteErrors {
+		if e.Code != duplicateKeyCode {
+			return false
+		}
+	}
+	return true
+}
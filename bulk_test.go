@@ -0,0 +1,135 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"testing"
+	"time"
+)
+
+func TestUpsertStreamInvalidBatchSize(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+	for _, size := range []int{0, -1} {
+		errs := c.UpsertStream(context.Background(), make(chan bson.M), "key", size, 0)
+		if err := <-errs; !errors.Is(err, ErrInvalidBatchSize) {
+			t.Errorf("UpsertStream(%d) error = %v, want ErrInvalidBatchSize", size, err)
+		}
+		if _, open := <-errs; open {
+			t.Errorf("UpsertStream(%d) left the error channel open", size)
+		}
+	}
+}
+
+func TestUpsertStream(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	in := make(chan bson.M)
+	errs := c.UpsertStream(ctx, in, "key", 2, 10*time.Millisecond)
+	go func() {
+		defer close(in)
+		for _, doc := range []bson.M{
+			{"key": "a", "n": 1},
+			{"key": "b", "n": 1},
+			{"key": "c", "n": 1},
+			{"key": "a", "n": 2},
+		} {
+			in <- doc
+		}
+	}()
+	for err := range errs {
+		t.Errorf("UpsertStream() error = %v", err)
+	}
+
+	docs, err := c.Find(ctx, nil, SortAsc("key"))
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("Find() returned %d documents, want 3", len(docs))
+	}
+	if n := docs[0]["n"]; n != int32(2) {
+		t.Errorf("document a has n = %v, want 2", n)
+	}
+}
+
+func TestBulkWriteOptions(t *testing.T) {
+	c := offlineCollection[bson.M](t, WithBypassValidation(), WithComment(func(context.Context) string { return "req-1" }))
+
+	opts := options.MergeBulkWriteOptions(c.bulkWriteOptions(context.Background(), []*options.BulkWriteOptions{options.BulkWrite().SetOrdered(false)})...)
+	if opts.BypassDocumentValidation == nil || !*opts.BypassDocumentValidation {
+		t.Error("bypass document validation not set")
+	}
+	if opts.Comment != "req-1" {
+		t.Errorf("comment = %v, want req-1", opts.Comment)
+	}
+	if opts.Ordered == nil || *opts.Ordered {
+		t.Error("caller's unordered option overridden")
+	}
+}
+
+func TestBulkUpsertIfNewer(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	if _, err := c.CreateIndexes(ctx, []mongo.IndexModel{{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)}}); err != nil {
+		t.Fatalf("CreateIndexes() error = %v", err)
+	}
+
+	if _, err := c.BulkUpsertIfNewer(ctx, []bson.M{{"key": "a", "v": 2, "name": "new"}, {"key": "b", "v": 1, "name": "new"}}, "key", "v"); err != nil {
+		t.Fatalf("BulkUpsertIfNewer() error = %v", err)
+	}
+	// a is stale and b is newer
+	if _, err := c.BulkUpsertIfNewer(ctx, []bson.M{{"key": "a", "v": 1, "name": "stale"}, {"key": "b", "v": 3, "name": "newer"}}, "key", "v"); err != nil {
+		t.Fatalf("BulkUpsertIfNewer() error = %v", err)
+	}
+
+	docs, err := c.Find(ctx, nil, SortAsc("key"))
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(docs) != 2 || docs[0]["name"] != "new" || docs[1]["name"] != "newer" {
+		t.Errorf("Find() = %v, want a new and b newer", docs)
+	}
+}
+
+func TestBulkWriteOrdering(t *testing.T) {
+	ctx := context.Background()
+
+	models := func(t *testing.T) []mongo.WriteModel {
+		var result []mongo.WriteModel
+		for _, id := range []string{"a", "a", "b"} {
+			model, err := InsertOneModel(bson.M{"_id": id})
+			if err != nil {
+				t.Fatalf("InsertOneModel() error = %v", err)
+			}
+			result = append(result, model)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		ordered bool
+		want    int64
+	}{
+		{"ordered", true, 1},
+		{"unordered", false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testCollection[bson.M](t)
+
+			result, err := c.BulkWrite(ctx, models(t), options.BulkWrite().SetOrdered(tt.ordered))
+			if !mongo.IsDuplicateKeyError(err) {
+				t.Fatalf("BulkWrite() error = %v, want a duplicate key error", err)
+			}
+			if result.InsertedCount != tt.want {
+				t.Errorf("BulkWrite() inserted %d, want %d", result.InsertedCount, tt.want)
+			}
+		})
+	}
+}
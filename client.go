@@ -2,17 +2,152 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/rs/zerolog"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"time"
 )
 
-const ErrMsgClient = "failed to create mongodb client"
+const (
+	ErrMsgClient = "failed to create mongodb client"
 
-func GetClient(ctx context.Context, uri string, log zerolog.Logger) *mongo.Client {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	authFailedCode = 18
+)
+
+var (
+	ErrUnreachable = errors.New("mongodb server unreachable")
+	ErrAuthFailed  = errors.New("mongodb authentication failed")
+)
+
+// GetClient is NewClient exiting the process on failure.
+func GetClient(ctx context.Context, uri string, log zerolog.Logger, opts ...*options.ClientOptions) *mongo.Client {
+	client, err := NewClient(ctx, uri, log, opts...)
 	if err != nil {
 		log.Fatal().Err(err).Msg(ErrMsgClient)
 	}
 	return client
 }
+
+// NewClient connects to uri. Options in opts are applied on top of the URI,
+// e.g. options.Client().SetMaxPoolSize(n).SetMaxConnecting(m) to bound the pool.
+// The driver has no separate bound on waiting for a free connection and
+// ignores waitQueueTimeoutMS: a checkout waits until the operation's context
+// is done, i.e. up to the collection's timeout. To fail fast when the pool is
+// exhausted, give latency-sensitive collections a short WithTimeout or pass
+// a context with a short deadline; IsPoolExhausted then tells the case apart.
+func NewClient(ctx context.Context, uri string, log zerolog.Logger, opts ...*options.ClientOptions) (*mongo.Client, error) {
+	opts = append([]*options.ClientOptions{options.Client().ApplyURI(uri)}, opts...)
+
+	client, err := mongo.Connect(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Msg("mongodb client created")
+
+	return client, nil
+}
+
+// ConnectWithRetry connects to uri and pings it, trying again with
+// exponential backoff until the server answers, attempts are exhausted or
+// ctx is done. It returns the last error on failure.
+func ConnectWithRetry(ctx context.Context, uri string, attempts int, backoff time.Duration, log zerolog.Logger) (*mongo.Client, error) {
+	for attempt := 1; ; attempt++ {
+		client, err := NewClient(ctx, uri, log)
+		if err == nil {
+			if err = Ping(ctx, client); err == nil {
+				return client, nil
+			}
+			_ = client.Disconnect(context.Background())
+		}
+		if attempt >= attempts {
+			return nil, err
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", backoff).Msg("mongodb connect failed, retrying")
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Ping checks that the primary is reachable, e.g. for a readiness probe.
+// Failures match ErrAuthFailed when the credentials were rejected and
+// ErrUnreachable when no server could be selected.
+func Ping(ctx context.Context, client *mongo.Client) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		return pingError(err)
+	}
+	return nil
+}
+
+func (c *Collection[T]) Ping(ctx context.Context) (err error) {
+	ctx, o := c.begin(ctx, "Ping")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if err = c.Inner.Database().Client().Ping(ctx, readpref.Primary()); err != nil {
+		return pingError(err)
+	}
+	return nil
+}
+
+func pingError(err error) error {
+	var (
+		authErr *auth.Error
+		sse     topology.ServerSelectionError
+		se      mongo.ServerError
+	)
+
+	switch {
+	case errors.As(err, &authErr), errors.As(err, &se) && se.HasErrorCode(authFailedCode):
+		return fmt.Errorf("%w: %v", ErrAuthFailed, err)
+	case errors.As(err, &sse):
+		// the reason the servers couldn't be selected is in their description
+		for _, s := range sse.Desc.Servers {
+			if errors.As(s.LastError, &authErr) {
+				return fmt.Errorf("%w: %v", ErrAuthFailed, err)
+			}
+		}
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	return err
+}
+
+// Disconnect closes client within the default timeout, meant to be deferred
+// at shutdown. Disconnecting a nil or already disconnected client is a no-op.
+func Disconnect(ctx context.Context, client *mongo.Client, log zerolog.Logger) error {
+	if client == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := client.Disconnect(ctx); err != nil {
+		if errors.Is(err, mongo.ErrClientDisconnected) {
+			return nil
+		}
+		log.Error().Err(err).Msg("mongodb client disconnect failed")
+		return err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("mongodb client disconnected")
+
+	return nil
+}
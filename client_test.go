@@ -0,0 +1,125 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPingError(t *testing.T) {
+	unreachable := topology.ServerSelectionError{
+		Wrapped: errors.New("server selection timeout"),
+		Desc:    description.Topology{Servers: []description.Server{{LastError: errors.New("connection refused")}}},
+	}
+	rejected := topology.ServerSelectionError{
+		Wrapped: errors.New("server selection timeout"),
+		Desc:    description.Topology{Servers: []description.Server{{LastError: &auth.Error{}}}},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unreachable", unreachable, ErrUnreachable},
+		{"rejected while selecting", rejected, ErrAuthFailed},
+		{"auth error", &auth.Error{}, ErrAuthFailed},
+		{"auth failed code", mongo.CommandError{Code: authFailedCode}, ErrAuthFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := pingError(tt.err); !errors.Is(err, tt.want) {
+				t.Errorf("pingError() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	other := errors.New("boom")
+	if err := pingError(other); err != other {
+		t.Errorf("pingError() = %v, want other errors unchanged", err)
+	}
+}
+
+func TestPingUnreachable(t *testing.T) {
+	c := offlineCollection[struct{}](t)
+	if err := c.Ping(context.Background()); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("Ping() error = %v, want ErrUnreachable", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI not set")
+	}
+
+	client, err := NewClient(context.Background(), uri, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err = Ping(context.Background(), client); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+const unreachableURI = "mongodb://127.0.0.1:1/?serverSelectionTimeoutMS=50"
+
+func TestConnectWithRetryExhausted(t *testing.T) {
+	client, err := ConnectWithRetry(context.Background(), unreachableURI, 2, time.Millisecond, zerolog.Nop())
+	if client != nil || !errors.Is(err, ErrUnreachable) {
+		t.Errorf("ConnectWithRetry() = %v, %v, want ErrUnreachable", client, err)
+	}
+}
+
+func TestConnectWithRetryCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := ConnectWithRetry(ctx, unreachableURI, 10, time.Hour, zerolog.Nop())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ConnectWithRetry() error = %v, want context.Canceled", err)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("ConnectWithRetry() returned after %v, want the backoff aborted on cancel", took)
+	}
+}
+
+func TestDisconnect(t *testing.T) {
+	ctx := context.Background()
+	if err := Disconnect(ctx, nil, zerolog.Nop()); err != nil {
+		t.Errorf("Disconnect(nil) error = %v", err)
+	}
+
+	client, err := NewClient(ctx, unreachableURI, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err = Disconnect(ctx, client, zerolog.Nop()); err != nil {
+		t.Errorf("Disconnect() error = %v", err)
+	}
+
+	c := NewCollection[bson.M](client.Database("test"), "items", zerolog.Nop())
+	if _, err = c.FindOne(ctx, bson.M{}); !errors.Is(err, mongo.ErrClientDisconnected) {
+		t.Errorf("FindOne() error = %v after Disconnect, want ErrClientDisconnected", err)
+	}
+
+	var buf bytes.Buffer
+	if err = Disconnect(ctx, client, zerolog.New(&buf)); err != nil {
+		t.Errorf("Disconnect() error = %v on an already disconnected client", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("second Disconnect() logged %s, want a silent no-op", buf.String())
+	}
+}
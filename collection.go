@@ -0,0 +1,1127 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ErrMsgFromHex = "failed to convert hex to object id due to error: %w"
+
+var (
+	ErrTooManyResults      = errors.New("query returned more documents than allowed")
+	ErrInvalidRename       = errors.New("invalid field rename")
+	ErrInvalidPollInterval = errors.New("poll interval must be positive")
+)
+
+const (
+	timeout             = 10 * time.Second
+	estimateSampleSize  = 1000
+	namespaceExistsCode = 48
+)
+
+// NoTimeout passed to WithTimeout leaves cancellation entirely to the
+// caller's context, e.g. for long migrations.
+const NoTimeout time.Duration = -1
+
+type (
+	Collection[T any] struct {
+		Inner *mongo.Collection
+		Log   zerolog.Logger
+		Config
+		slow    *slowRing
+		session mongo.Session
+	}
+
+	Config struct {
+		BypassValidation   bool
+		CheckTimePrecision bool
+		Encryption         *mongo.ClientEncryption
+		MaxResults         int
+		SlowThreshold      time.Duration
+		ExplainSlow        bool
+		Comment            func(ctx context.Context) string
+		Timeout            time.Duration
+		SlowSamples        int
+		LogFilters         FilterLogging
+		Tracer             Tracer
+		Metrics            Metrics
+	}
+
+	CollectionOption func(o *collectionOptions)
+
+	collectionOptions struct {
+		Config
+		inner []*options.CollectionOptions
+	}
+)
+
+func WithCollectionOptions(opts ...*options.CollectionOptions) CollectionOption {
+	return func(o *collectionOptions) {
+		o.inner = append(o.inner, opts...)
+	}
+}
+
+func WithBypassValidation() CollectionOption {
+	return func(o *collectionOptions) {
+		o.BypassValidation = true
+	}
+}
+
+func WithTimePrecisionCheck() CollectionOption {
+	return func(o *collectionOptions) {
+		o.CheckTimePrecision = true
+	}
+}
+
+// WithMaxResults makes Find and aggregations returning []T fail with
+// ErrTooManyResults instead of loading more than n documents into memory.
+func WithMaxResults(n int) CollectionOption {
+	return func(o *collectionOptions) {
+		o.MaxResults = n
+	}
+}
+
+func WithSlowThreshold(d time.Duration) CollectionOption {
+	return func(o *collectionOptions) {
+		o.SlowThreshold = d
+	}
+}
+
+// WithSlowExplain makes operations slower than the slow threshold log their
+// query plan at warn level. It costs an extra explain round trip per slow
+// operation, so it is off by default.
+func WithSlowExplain() CollectionOption {
+	return func(o *collectionOptions) {
+		o.ExplainSlow = true
+	}
+}
+
+// WithTimeout bounds each operation to d instead of the default 10 seconds,
+// or not at all when d is NoTimeout. A sooner deadline already set on the
+// caller's context still wins.
+func WithTimeout(d time.Duration) CollectionOption {
+	return func(o *collectionOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithComment sets the comment of each operation to fn(ctx), typically the
+// trace id of the current span, so that operations showing up in the
+// profiler or the slow query log can be correlated with their trace.
+// A comment passed explicitly via opts takes precedence. Collection and
+// index management commands, e.g. in EnsureExists, take no comment in the
+// driver and are sent without one.
+func WithComment(fn func(ctx context.Context) string) CollectionOption {
+	return func(o *collectionOptions) {
+		o.Comment = fn
+	}
+}
+
+func NewCollection[T any](db *mongo.Database, name string, log zerolog.Logger, opts ...CollectionOption) *Collection[T] {
+	o := &collectionOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	c := &Collection[T]{Inner: db.Collection(name, o.inner...), Log: log, Config: o.Config}
+	if o.SlowSamples > 0 {
+		c.slow = newSlowRing(o.SlowSamples)
+	}
+	return c
+}
+
+// NormalizeFilter turns the shorthand filters accepted by the methods into
+// bson: nil matches everything, a hex string or ObjectID matches that _id,
+// and a slice of either matches any of the ids, so an empty slice matches
+// nothing.
+func NormalizeFilter(filter any) (any, error) {
+	switch f := filter.(type) {
+	case nil:
+		return bson.M{}, nil
+	case string:
+		id, err := primitive.ObjectIDFromHex(f)
+		if err != nil {
+			return nil, fmt.Errorf(ErrMsgFromHex, err)
+		}
+		return bson.M{"_id": id}, nil
+	case primitive.ObjectID:
+		return bson.M{"_id": f}, nil
+	case []string:
+		ids, err := ObjectIDsFromHex(f)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"_id": bson.M{"$in": ids}}, nil
+	case []primitive.ObjectID:
+		if f == nil {
+			f = []primitive.ObjectID{}
+		}
+		return bson.M{"_id": bson.M{"$in": f}}, nil
+	case Filter:
+		return f.Build(), nil
+	}
+	return filter, nil
+}
+
+func ObjectIDsFromHex(ids []string) ([]primitive.ObjectID, error) {
+	result := make([]primitive.ObjectID, len(ids))
+	for i, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf(ErrMsgFromHex, err)
+		}
+		result[i] = oid
+	}
+	return result, nil
+}
+
+func (c *Collection[T]) InsertOne(ctx context.Context, doc T, opts ...*options.InsertOneOptions) (id string, err error) {
+	ctx, o := c.begin(ctx, "InsertOne")
+	defer func() { o.end(err) }()
+
+	data, err := omitNilID(doc)
+	if err != nil {
+		return "", err
+	}
+
+	c.checkTimePrecision(doc)
+
+	opts = c.insertOptions(ctx, opts)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document insert")
+
+	start := time.Now()
+	result, err := c.Inner.InsertOne(ctx, data, opts...)
+	if err != nil {
+		return "", fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	id = idString(result.InsertedID)
+
+	c.debug(ctx).Str("id", id).Dur("took", time.Since(start)).Msg("document inserted")
+
+	return id, nil
+}
+
+func (c *Collection[T]) FindOne(ctx context.Context, filter any, opts ...*options.FindOneOptions) (doc T, err error) {
+	ctx, o := c.begin(ctx, "FindOne")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return doc, err
+	}
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.FindOneOptions{options.FindOne().SetComment(comment)}, opts...)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document find")
+
+	start := time.Now()
+	if doc, err = DecodeOne[T](c.Inner.FindOne(ctx, filter, opts...)); err != nil {
+		return doc, err
+	}
+
+	took := time.Since(start)
+	c.debug(ctx).Func(c.logFilter(filter)).Dur("took", took).Msg("document found")
+
+	c.slowQuery("findOne", filter, took)
+
+	return doc, nil
+}
+
+func (c *Collection[T]) Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]T, error) {
+	return find[T, T](ctx, c, "Find", filter, opts...)
+}
+
+// find runs Find as op, decoding the documents into R.
+func find[T, R any](ctx context.Context, c *Collection[T], op string, filter any, opts ...*options.FindOptions) (docs []R, err error) {
+	ctx, o := c.begin(ctx, op)
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if c.MaxResults > 0 {
+		opts = append([]*options.FindOptions{options.Find().SetLimit(int64(c.MaxResults) + 1)}, opts...)
+	}
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.FindOptions{options.Find().SetComment(comment)}, opts...)
+	}
+
+	c.debug(ctx).Msg("documents find")
+
+	start := time.Now()
+	cur, err := c.Inner.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	if docs, err = decodeAll[R](ctx, cur, c.MaxResults); err != nil {
+		return nil, err
+	}
+
+	o.count(len(docs))
+
+	took := time.Since(start)
+	c.debug(ctx).Int("count", len(docs)).Func(c.logFilter(filter)).Dur("took", took).Msg("documents found")
+
+	c.slowQuery("find", filter, took)
+
+	return docs, nil
+}
+
+func (c *Collection[T]) UpdateOne(ctx context.Context, filter any, update any, opts ...*options.UpdateOptions) (err error) {
+	ctx, o := c.begin(ctx, "UpdateOne")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return err
+	}
+
+	opts = c.updateOptions(ctx, opts)
+
+	c.checkTimePrecision(update)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document update")
+
+	start := time.Now()
+	result, err := c.Inner.UpdateOne(ctx, filter, update, opts...)
+	if err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	if result.MatchedCount == 0 && result.ModifiedCount == 0 && result.UpsertedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	took := time.Since(start)
+	c.debug(ctx).Func(c.logFilter(filter)).Dur("took", took).Msg("document updated")
+
+	c.slowQuery("updateOne", filter, took)
+
+	return nil
+}
+
+// Upsert applies update to the document matching filter, inserting it if
+// missing. It returns the id of the inserted document, or an empty string
+// when an existing one was updated.
+func (c *Collection[T]) Upsert(ctx context.Context, filter any, update any, opts ...*options.UpdateOptions) (upsertedID string, err error) {
+	ctx, o := c.begin(ctx, "Upsert")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return "", err
+	}
+
+	opts = append(c.updateOptions(ctx, opts), options.Update().SetUpsert(true))
+
+	c.checkTimePrecision(update)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document upsert")
+
+	start := time.Now()
+	result, err := c.Inner.UpdateOne(ctx, filter, update, opts...)
+	if err != nil {
+		return "", fmt.Errorf(ErrMsgQuery, err)
+	}
+	if result.UpsertedID != nil {
+		upsertedID = idString(result.UpsertedID)
+	}
+
+	took := time.Since(start)
+	c.debug(ctx).Str("id", upsertedID).Func(c.logFilter(filter)).Dur("took", took).Msg("document upserted")
+
+	c.slowQuery("upsert", filter, took)
+
+	return upsertedID, nil
+}
+
+// UpdateMany returns the matched and modified counts; matching nothing is
+// not an error.
+func (c *Collection[T]) UpdateMany(ctx context.Context, filter any, update any, opts ...*options.UpdateOptions) (matched, modified uint64, err error) {
+	ctx, o := c.begin(ctx, "UpdateMany")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return 0, 0, err
+	}
+
+	opts = c.updateOptions(ctx, opts)
+
+	c.checkTimePrecision(update)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("documents update")
+
+	start := time.Now()
+	result, err := c.Inner.UpdateMany(ctx, filter, update, opts...)
+	if err != nil {
+		return 0, 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(int(result.ModifiedCount))
+
+	took := time.Since(start)
+	c.debug(ctx).Int64("matched", result.MatchedCount).Int64("modified", result.ModifiedCount).Func(c.logFilter(filter)).Dur("took", took).Msg("documents updated")
+
+	c.slowQuery("updateMany", filter, took)
+
+	return uint64(result.MatchedCount), uint64(result.ModifiedCount), nil
+}
+
+func (c *Collection[T]) ReplaceOne(ctx context.Context, filter any, replacement T, opts ...*options.ReplaceOptions) (err error) {
+	ctx, o := c.begin(ctx, "ReplaceOne")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return err
+	}
+
+	data, err := omitNilID(replacement)
+	if err != nil {
+		return err
+	}
+
+	opts = c.replaceOptions(ctx, opts)
+
+	c.checkTimePrecision(replacement)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document replace")
+
+	start := time.Now()
+	result, err := c.Inner.ReplaceOne(ctx, filter, data, opts...)
+	if err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	if result.MatchedCount == 0 && result.ModifiedCount == 0 && result.UpsertedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	took := time.Since(start)
+	c.debug(ctx).Func(c.logFilter(filter)).Dur("took", took).Msg("document replaced")
+
+	c.slowQuery("replaceOne", filter, took)
+
+	return nil
+}
+
+// FindOneAndUpdate atomically updates the document matching filter and
+// returns it as it was before the update, unless opts ask for
+// options.After via SetReturnDocument. It returns mongo.ErrNoDocuments when
+// nothing matched and no upsert happened.
+func (c *Collection[T]) FindOneAndUpdate(ctx context.Context, filter any, update any, opts ...*options.FindOneAndUpdateOptions) (doc T, err error) {
+	ctx, o := c.begin(ctx, "FindOneAndUpdate")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return doc, err
+	}
+
+	if c.BypassValidation {
+		opts = append([]*options.FindOneAndUpdateOptions{options.FindOneAndUpdate().SetBypassDocumentValidation(true)}, opts...)
+	}
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.FindOneAndUpdateOptions{options.FindOneAndUpdate().SetComment(comment)}, opts...)
+	}
+
+	c.checkTimePrecision(update)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document find and update")
+
+	start := time.Now()
+	if doc, err = DecodeOne[T](c.Inner.FindOneAndUpdate(ctx, filter, update, opts...)); err != nil {
+		return doc, err
+	}
+
+	took := time.Since(start)
+	c.debug(ctx).Func(c.logFilter(filter)).Dur("took", took).Msg("document found and updated")
+
+	c.slowQuery("findOneAndUpdate", filter, took)
+
+	return doc, nil
+}
+
+// UpsertAndGet applies update to the document matching filter, inserting
+// it if missing, and returns the resulting document.
+func (c *Collection[T]) UpsertAndGet(ctx context.Context, filter any, update any) (T, error) {
+	return c.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+}
+
+func (c *Collection[T]) DeleteOne(ctx context.Context, filter any, opts ...*options.DeleteOptions) (err error) {
+	ctx, o := c.begin(ctx, "DeleteOne")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return err
+	}
+
+	opts = c.deleteOptions(ctx, opts)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document delete")
+
+	start := time.Now()
+	result, err := c.Inner.DeleteOne(ctx, filter, opts...)
+	if err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	took := time.Since(start)
+	c.debug(ctx).Func(c.logFilter(filter)).Dur("took", took).Msg("document deleted")
+
+	c.slowQuery("deleteOne", filter, took)
+
+	return nil
+}
+
+// DeleteMany returns the number of deleted documents; deleting nothing is
+// not an error.
+func (c *Collection[T]) DeleteMany(ctx context.Context, filter any, opts ...*options.DeleteOptions) (deleted uint64, err error) {
+	ctx, o := c.begin(ctx, "DeleteMany")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return 0, err
+	}
+
+	opts = c.deleteOptions(ctx, opts)
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("documents delete")
+
+	start := time.Now()
+	result, err := c.Inner.DeleteMany(ctx, filter, opts...)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(int(result.DeletedCount))
+
+	took := time.Since(start)
+	c.debug(ctx).Int64("count", result.DeletedCount).Func(c.logFilter(filter)).Dur("took", took).Msg("documents deleted")
+
+	c.slowQuery("deleteMany", filter, took)
+
+	return uint64(result.DeletedCount), nil
+}
+
+func (c *Collection[T]) CreatedAfter(ctx context.Context, t time.Time, opts ...*options.FindOptions) ([]T, error) {
+	return c.Find(ctx, bson.M{"_id": bson.M{"$gt": ObjectIDFromTime(t)}}, opts...)
+}
+
+func (c *Collection[T]) Random(ctx context.Context, filter any) (doc T, err error) {
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return doc, err
+	}
+
+	docs, err := Aggregate[T, T](ctx, c, NewPipeline().Match(filter).Stage("$sample", bson.M{"size": 1}).Build())
+	if err != nil {
+		return doc, err
+	}
+	if len(docs) == 0 {
+		return doc, mongo.ErrNoDocuments
+	}
+	return docs[0], nil
+}
+
+func (c *Collection[T]) EnsureExists(ctx context.Context, indexes []mongo.IndexModel, opts ...*options.CreateCollectionOptions) (err error) {
+	ctx, o := c.begin(ctx, "EnsureExists")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	db := c.Inner.Database()
+
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": c.Inner.Name()})
+	if err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	if len(names) == 0 {
+		c.debug(ctx).Msg("collection create")
+
+		if err = db.CreateCollection(ctx, c.Inner.Name(), opts...); err != nil {
+			var cmdErr mongo.CommandError
+			// another instance created it in the meantime
+			if !errors.As(err, &cmdErr) || cmdErr.Code != namespaceExistsCode {
+				return fmt.Errorf(ErrMsgQuery, err)
+			}
+		}
+	}
+
+	if len(indexes) > 0 {
+		if _, err = c.Inner.Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf(ErrMsgQuery, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Collection[T]) FindByIDsMap(ctx context.Context, ids []string) (_ map[string]T, err error) {
+	ctx, o := c.begin(ctx, "FindByIDsMap")
+	defer func() { o.end(err) }()
+
+	oids, err := ObjectIDsFromHex(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Int("ids", len(ids)).Msg("documents find")
+
+	start := time.Now()
+	cur, err := c.Inner.Find(ctx, bson.M{"_id": bson.M{"$in": oids}}, c.findOptions(ctx, nil)...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	docs := make(map[string]T, len(ids))
+	for cur.Next(ctx) {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return nil, fmt.Errorf(ErrMsgDecode, err)
+		}
+		docs[rawIDString(cur.Current.Lookup("_id"))] = doc
+	}
+	if err = cur.Err(); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(len(docs))
+
+	c.debug(ctx).Int("count", len(docs)).Dur("took", time.Since(start)).Msg("documents found")
+
+	return docs, nil
+}
+
+func (c *Collection[T]) FindIDs(ctx context.Context, filter any, opts ...*options.FindOptions) (ids []string, err error) {
+	ctx, o := c.begin(ctx, "FindIDs")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("document ids find")
+
+	start := time.Now()
+	cur, err := c.Inner.Find(ctx, filter, c.findOptions(ctx, append(opts[:len(opts):len(opts)], options.Find().SetProjection(bson.M{"_id": 1})))...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(ctx) {
+		ids = append(ids, rawIDString(cur.Current.Lookup("_id")))
+	}
+	if err = cur.Err(); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(len(ids))
+
+	c.debug(ctx).Int("count", len(ids)).Dur("took", time.Since(start)).Msg("document ids found")
+
+	return ids, nil
+}
+
+// DeleteManyReturning deletes exactly the documents it returns, by _id, so
+// documents matching filter after the read are left alone. Pass a session
+// context with an active transaction to make the read and delete atomic.
+func (c *Collection[T]) DeleteManyReturning(ctx context.Context, filter any) (docs []T, err error) {
+	ctx, o := c.begin(ctx, "DeleteManyReturning")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("documents delete")
+
+	start := time.Now()
+	cur, err := c.Inner.Find(ctx, filter, c.findOptions(ctx, nil)...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	var ids bson.A
+	for cur.Next(ctx) {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return nil, fmt.Errorf(ErrMsgDecode, err)
+		}
+		docs = append(docs, doc)
+		ids = append(ids, cur.Current.Lookup("_id"))
+	}
+	if err = cur.Err(); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	if _, err = c.Inner.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, c.deleteOptions(ctx, nil)...); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(len(docs))
+
+	c.debug(ctx).Int("count", len(docs)).Dur("took", time.Since(start)).Msg("documents deleted")
+
+	return docs, nil
+}
+
+// EstimateMatches extrapolates the number of documents matching filter from
+// a random sample, which stays cheap regardless of the collection size.
+func (c *Collection[T]) EstimateMatches(ctx context.Context, filter any) (_ uint64, err error) {
+	ctx, o := c.begin(ctx, "EstimateMatches")
+	defer func() { o.end(err) }()
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	countOpts := options.EstimatedDocumentCount()
+	if comment, ok := c.comment(ctx); ok {
+		countOpts.SetComment(comment)
+	}
+
+	total, err := c.Inner.EstimatedDocumentCount(ctx, countOpts)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+	if total == 0 {
+		return 0, nil
+	}
+
+	sampled := int64(estimateSampleSize)
+	if total < sampled {
+		sampled = total
+	}
+
+	cur, err := c.Inner.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sample", Value: bson.M{"size": sampled}}},
+		{{Key: "$match", Value: filter}},
+		{{Key: "$count", Value: "n"}},
+	}, c.aggregateOptions(ctx, nil)...)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	result, err := DecodeAll[struct {
+		N int64 `bson:"n"`
+	}](ctx, cur)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+
+	return uint64(result[0].N * total / sampled), nil
+}
+
+func (c *Collection[T]) insertOptions(ctx context.Context, opts []*options.InsertOneOptions) []*options.InsertOneOptions {
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.InsertOneOptions{options.InsertOne().SetComment(comment)}, opts...)
+	}
+	if c.BypassValidation {
+		return append([]*options.InsertOneOptions{options.InsertOne().SetBypassDocumentValidation(true)}, opts...)
+	}
+	return opts
+}
+
+func (c *Collection[T]) updateOptions(ctx context.Context, opts []*options.UpdateOptions) []*options.UpdateOptions {
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.UpdateOptions{options.Update().SetComment(comment)}, opts...)
+	}
+	if c.BypassValidation {
+		return append([]*options.UpdateOptions{options.Update().SetBypassDocumentValidation(true)}, opts...)
+	}
+	return opts
+}
+
+func (c *Collection[T]) deleteOptions(ctx context.Context, opts []*options.DeleteOptions) []*options.DeleteOptions {
+	if comment, ok := c.comment(ctx); ok {
+		return append([]*options.DeleteOptions{options.Delete().SetComment(comment)}, opts...)
+	}
+	return opts
+}
+
+func (c *Collection[T]) findOptions(ctx context.Context, opts []*options.FindOptions) []*options.FindOptions {
+	if comment, ok := c.comment(ctx); ok {
+		return append([]*options.FindOptions{options.Find().SetComment(comment)}, opts...)
+	}
+	return opts
+}
+
+func (c *Collection[T]) aggregateOptions(ctx context.Context, opts []*options.AggregateOptions) []*options.AggregateOptions {
+	if comment, ok := c.comment(ctx); ok {
+		return append([]*options.AggregateOptions{options.Aggregate().SetComment(comment)}, opts...)
+	}
+	return opts
+}
+
+func (c *Collection[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = c.withSession(ctx)
+
+	d := c.Timeout
+	switch {
+	case d == NoTimeout:
+		return context.WithCancel(ctx)
+	case d <= 0:
+		d = timeout
+	}
+	// the derived context keeps the parent's deadline when it is sooner
+	return context.WithTimeout(ctx, d)
+}
+
+// debug starts a debug log event attributed to the request behind ctx.
+func (c *Collection[T]) debug(ctx context.Context) *zerolog.Event {
+	e := c.Log.Debug().Str("collection", c.Inner.Name())
+	md := RequestMetadata(ctx)
+	for _, k := range sortedKeys(md) {
+		e = e.Str(k, md[k])
+	}
+	return e
+}
+
+// comment returns the comment the Comment hook computes for ctx, if any.
+func (c *Collection[T]) comment(ctx context.Context) (string, bool) {
+	if c.Comment == nil {
+		return "", false
+	}
+	comment := c.Comment(ctx)
+	return comment, comment != ""
+}
+
+// CompareAndSet applies update only while field still equals expected and
+// reports whether it did; a failed precondition is not an error.
+func (c *Collection[T]) CompareAndSet(ctx context.Context, id string, field string, expected any, update bson.M) (_ bool, err error) {
+	ctx, o := c.begin(ctx, "CompareAndSet")
+	defer func() { o.end(err) }()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf(ErrMsgFromHex, err)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Str("id", id).Str("field", field).Msg("document compare and set")
+
+	start := time.Now()
+	result, err := c.Inner.UpdateOne(ctx, bson.M{"_id": oid, field: expected}, update, c.updateOptions(ctx, nil)...)
+	if err != nil {
+		return false, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Str("id", id).Bool("applied", result.MatchedCount > 0).Dur("took", time.Since(start)).Msg("document compared and set")
+
+	return result.MatchedCount > 0, nil
+}
+
+func (c *Collection[T]) WithReadConcern(rc *readconcern.ReadConcern) *Collection[T] {
+	return c.clone(options.Collection().SetReadConcern(rc))
+}
+
+func (c *Collection[T]) clone(opts ...*options.CollectionOptions) *Collection[T] {
+	// the driver never fails to clone, the error is only part of its API
+	inner, _ := c.Inner.Clone(opts...)
+
+	cc := *c
+	cc.Inner = inner
+	return &cc
+}
+
+// UpdateArrayElement updates array elements selected by arrayFilters, e.g.
+//
+//	c.UpdateArrayElement(ctx, id,
+//		[]any{bson.M{"item.sku": "abc"}},
+//		bson.M{"$set": bson.M{"items.$[item].qty": 2}})
+func (c *Collection[T]) UpdateArrayElement(ctx context.Context, filter any, arrayFilters []any, update bson.M) error {
+	return c.UpdateOne(ctx, filter, update, options.Update().SetArrayFilters(options.ArrayFilters{Filters: arrayFilters}))
+}
+
+func (c *Collection[T]) replaceOptions(ctx context.Context, opts []*options.ReplaceOptions) []*options.ReplaceOptions {
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.ReplaceOptions{options.Replace().SetComment(comment)}, opts...)
+	}
+	if c.BypassValidation {
+		return append([]*options.ReplaceOptions{options.Replace().SetBypassDocumentValidation(true)}, opts...)
+	}
+	return opts
+}
+
+func (c *Collection[T]) checkTimePrecision(doc any) {
+	if !c.CheckTimePrecision {
+		return
+	}
+	if paths := TruncatedTimes(doc); len(paths) > 0 {
+		c.Log.Warn().Str("collection", c.Inner.Name()).Strs("fields", paths).Msg("time truncated to milliseconds")
+	}
+}
+
+// FindByIDsChunked looks ids up with one $in query per chunkSize ids and
+// concatenates the results, keeping each query small.
+func (c *Collection[T]) FindByIDsChunked(ctx context.Context, ids []string, chunkSize int) ([]T, error) {
+	var docs []T
+	for _, chunk := range chunkIDs(ids, chunkSize) {
+		result, err := c.findChunk(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, result...)
+	}
+	return docs, nil
+}
+
+// FindByIDsParallel is FindByIDsChunked with up to concurrency chunk queries
+// in flight. The first failing chunk cancels the others and its error is
+// returned; results keep the order of the chunks.
+func (c *Collection[T]) FindByIDsParallel(ctx context.Context, ids []string, chunkSize, concurrency int) ([]T, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		chunks  = chunkIDs(ids, chunkSize)
+		results = make([][]T, len(chunks))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+		once    sync.Once
+		first   error
+	)
+
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			docs, err := c.findChunk(ctx, chunk)
+			if err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+				return
+			}
+			results[i] = docs
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if first != nil {
+		return nil, first
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var docs []T
+	for _, r := range results {
+		docs = append(docs, r...)
+	}
+	return docs, nil
+}
+
+func (c *Collection[T]) findChunk(ctx context.Context, ids []string) ([]T, error) {
+	oids, err := ObjectIDsFromHex(ids)
+	if err != nil {
+		return nil, err
+	}
+	return c.Find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+}
+
+func chunkIDs(ids []string, size int) [][]string {
+	if size <= 0 || size >= len(ids) {
+		return [][]string{ids}
+	}
+
+	chunks := make([][]string, 0, (len(ids)+size-1)/size)
+	for size < len(ids) {
+		ids, chunks = ids[size:], append(chunks, ids[:size])
+	}
+	return append(chunks, ids)
+}
+
+// WaitFor polls FindOne every pollInterval until the matching document
+// satisfies check, returning it, or until ctx is done.
+func (c *Collection[T]) WaitFor(ctx context.Context, filter any, check func(T) bool, pollInterval time.Duration) (T, error) {
+	if pollInterval <= 0 {
+		var zero T
+		return zero, ErrInvalidPollInterval
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		doc, err := c.FindOne(ctx, filter)
+		if err == nil && check(doc) {
+			return doc, nil
+		}
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return doc, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return doc, ctx.Err()
+		}
+	}
+}
+
+func (c *Collection[T]) AddToSetMany(ctx context.Context, filter any, field string, value any) (uint64, error) {
+	_, modified, err := c.UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{field: value}})
+	return modified, err
+}
+
+// RenameField renames oldName to newName in the documents matching filter and
+// returns how many were modified. Dotted paths are supported, but $rename
+// does not reach into arrays and fails when one name is a path prefix of
+// the other, so those are rejected upfront.
+func (c *Collection[T]) RenameField(ctx context.Context, oldName, newName string, filter any) (uint64, error) {
+	if oldName == newName || strings.HasPrefix(newName, oldName+".") || strings.HasPrefix(oldName, newName+".") {
+		return 0, fmt.Errorf("%w: %s to %s", ErrInvalidRename, oldName, newName)
+	}
+
+	// documents lacking the field would only count as matched
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	filter = bson.M{"$and": bson.A{filter, bson.M{oldName: bson.M{"$exists": true}}}}
+
+	_, modified, err := c.UpdateMany(ctx, filter, bson.M{"$rename": bson.M{oldName: newName}})
+	return modified, err
+}
+
+func decodeAll[R any](ctx context.Context, cur *mongo.Cursor, max int) ([]R, error) {
+	if max <= 0 {
+		return DecodeAll[R](ctx, cur)
+	}
+
+	var docs []R
+	for cur.Next(ctx) {
+		if len(docs) == max {
+			return nil, fmt.Errorf("%w: limit is %d", ErrTooManyResults, max)
+		}
+
+		var doc R
+		if err := cur.Decode(&doc); err != nil {
+			return nil, fmt.Errorf(ErrMsgDecode, err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	return docs, nil
+}
+
+// PatchStruct $sets the fields of partial on the document with the given id.
+// Unless includeZero is set, fields still holding their zero value are left
+// untouched, so only the fields the caller filled in are written.
+func (c *Collection[T]) PatchStruct(ctx context.Context, id string, partial T, includeZero bool) error {
+	fields, err := ToBson(partial)
+	if err != nil {
+		return err
+	}
+	delete(fields, "_id")
+
+	if !includeZero {
+		var zero T
+		zeros, err := ToBson(zero)
+		if err != nil {
+			return err
+		}
+		for k, v := range fields {
+			if z, ok := zeros[k]; ok && reflect.DeepEqual(v, z) {
+				delete(fields, k)
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return c.UpdateOne(ctx, id, bson.M{"$set": fields})
+}
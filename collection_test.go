@@ -0,0 +1,430 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCollection returns a collection in a database of its own, dropped
+// once the test is done. Tests using it need a server, given by
+// MONGODB_URI, and are skipped without one.
+func testCollection[T any](t *testing.T, opts ...CollectionOption) *Collection[T] {
+	t.Helper()
+
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, uri, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	db := client.Database(fmt.Sprintf("test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+
+	return NewCollection[T](db, "items", zerolog.Nop(), opts...)
+}
+
+// offlineCollection returns a collection whose client never reaches a
+// server, for tests of what happens before or instead of a round trip.
+func offlineCollection[T any](t *testing.T, opts ...CollectionOption) *Collection[T] {
+	t.Helper()
+
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1/?serverSelectionTimeoutMS=100"))
+	if err != nil {
+		t.Fatalf("mongo.NewClient() error = %v", err)
+	}
+	if err = client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	return NewCollection[T](client.Database("test"), "items", zerolog.Nop(), opts...)
+}
+
+// insertAll inserts docs into c, failing the test on the first error.
+func insertAll[T any](t *testing.T, c *Collection[T], docs ...T) []string {
+	t.Helper()
+
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		id, err := c.InsertOne(context.Background(), doc)
+		if err != nil {
+			t.Fatalf("InsertOne() error = %v", err)
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+func TestCollectionBsonM(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	id, err := c.InsertOne(ctx, bson.M{"name": "alice", "age": 30})
+	if err != nil {
+		t.Fatalf("InsertOne() error = %v", err)
+	}
+
+	doc, err := c.FindOne(ctx, id)
+	if err != nil {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	if doc["name"] != "alice" {
+		t.Errorf("FindOne() name = %v, want alice", doc["name"])
+	}
+
+	docs, err := c.Find(ctx, bson.M{"name": "alice"})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0]["name"] != "alice" {
+		t.Errorf("Find() = %v, want alice's document", docs)
+	}
+}
+
+func TestWaitForInvalidInterval(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+	check := func(bson.M) bool { return true }
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := c.WaitFor(context.Background(), nil, check, interval); !errors.Is(err, ErrInvalidPollInterval) {
+			t.Errorf("WaitFor(%v) error = %v, want ErrInvalidPollInterval", interval, err)
+		}
+	}
+}
+
+func TestChunkIDs(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		size int
+		want string
+	}{
+		{2, "[[a b] [c d] [e]]"},
+		{5, "[[a b c d e]]"},
+		{10, "[[a b c d e]]"},
+		{0, "[[a b c d e]]"},
+		{1, "[[a] [b] [c] [d] [e]]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(chunkIDs(ids, tt.size)); got != tt.want {
+			t.Errorf("chunkIDs(%d) = %s, want %s", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateManyCounts(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	insertAll(t, c, bson.M{"n": 1}, bson.M{"n": 1}, bson.M{"n": 2})
+
+	matched, modified, err := c.UpdateMany(ctx, bson.M{"n": bson.M{"$gte": 1}}, bson.M{"$set": bson.M{"n": 2}})
+	if err != nil {
+		t.Fatalf("UpdateMany() error = %v", err)
+	}
+	if matched != 3 || modified != 2 {
+		t.Errorf("UpdateMany() = %d matched, %d modified, want 3 and 2", matched, modified)
+	}
+
+	matched, modified, err = c.UpdateMany(ctx, bson.M{"n": 5}, bson.M{"$set": bson.M{"n": 6}})
+	if err != nil || matched != 0 || modified != 0 {
+		t.Errorf("UpdateMany() = %d, %d, %v, want nothing matched and no error", matched, modified, err)
+	}
+
+	matched, modified, err = c.UpdateMany(ctx, "bad", bson.M{"$set": bson.M{"n": 7}})
+	if !errors.Is(err, primitive.ErrInvalidHex) || matched != 0 || modified != 0 {
+		t.Errorf("UpdateMany(\"bad\") = %d, %d, %v, want an invalid hex error", matched, modified, err)
+	}
+	if n, err := c.Count(ctx, bson.M{"n": 7}); err != nil || n != 0 {
+		t.Errorf("Count() = %d, %v, want no document updated by the malformed filter", n, err)
+	}
+}
+
+func TestDeleteManyCount(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	ids := insertAll(t, c, bson.M{"n": 1}, bson.M{"n": 1}, bson.M{"n": 2})
+
+	deleted, err := c.DeleteMany(ctx, bson.M{"n": 1})
+	if err != nil {
+		t.Fatalf("DeleteMany() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteMany() = %d, want 2", deleted)
+	}
+
+	if deleted, err = c.DeleteMany(ctx, bson.M{"n": 1}); err != nil || deleted != 0 {
+		t.Errorf("DeleteMany() = %d, %v, want nothing deleted and no error", deleted, err)
+	}
+
+	if deleted, err = c.DeleteMany(ctx, ids[2]); err != nil || deleted != 1 {
+		t.Errorf("DeleteMany(id) = %d, %v, want the document with that id deleted", deleted, err)
+	}
+	if n, err := c.Count(ctx, nil); err != nil || n != 0 {
+		t.Errorf("Count() = %d, %v, want an empty collection", n, err)
+	}
+}
+
+type testItem struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	Name string             `bson:"name"`
+	N    int                `bson:"n"`
+}
+
+func TestReplaceOne(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[testItem](t)
+	ids := insertAll(t, c, testItem{Name: "a", N: 1})
+
+	if err := c.ReplaceOne(ctx, ids[0], testItem{Name: "b"}); err != nil {
+		t.Fatalf("ReplaceOne() error = %v", err)
+	}
+	doc, err := c.FindOne(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	if doc.Name != "b" || doc.N != 0 || doc.ID.Hex() != ids[0] {
+		t.Errorf("FindOne() = %+v, want b replacing a under the same _id", doc)
+	}
+
+	if err = c.ReplaceOne(ctx, primitive.NewObjectID(), testItem{Name: "c"}); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("ReplaceOne() error = %v, want ErrNoDocuments", err)
+	}
+
+	// ReplaceOne doesn't return the upserted id, so look the document up
+	if err = c.ReplaceOne(ctx, bson.M{"name": "d"}, testItem{Name: "d", N: 4}, options.Replace().SetUpsert(true)); err != nil {
+		t.Fatalf("ReplaceOne() with upsert error = %v", err)
+	}
+	if n, err := c.Count(ctx, nil); err != nil || n != 2 {
+		t.Errorf("Count() = %d, %v, want 2 after the upsert", n, err)
+	}
+	upserted, err := c.FindOne(ctx, bson.M{"name": "d"})
+	if err != nil {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	if upserted.ID.IsZero() || upserted.ID.Hex() == ids[0] || upserted.N != 4 {
+		t.Errorf("FindOne() = %+v, want the upserted d under a new _id", upserted)
+	}
+}
+
+func TestFindOneAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[testItem](t)
+	ids := insertAll(t, c, testItem{Name: "a", N: 1})
+
+	before, err := c.FindOneAndUpdate(ctx, ids[0], Inc("n", 1))
+	if err != nil {
+		t.Fatalf("FindOneAndUpdate() error = %v", err)
+	}
+	if before.N != 1 {
+		t.Errorf("FindOneAndUpdate() n = %d, want the value before the update, 1", before.N)
+	}
+
+	after, err := c.FindOneAndUpdate(ctx, ids[0], Inc("n", 1), options.FindOneAndUpdate().SetReturnDocument(options.After))
+	if err != nil {
+		t.Fatalf("FindOneAndUpdate() error = %v", err)
+	}
+	if after.N != 3 {
+		t.Errorf("FindOneAndUpdate() n = %d, want the value after the update, 3", after.N)
+	}
+
+	if _, err = c.FindOneAndUpdate(ctx, primitive.NewObjectID(), Inc("n", 1)); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("FindOneAndUpdate() error = %v, want ErrNoDocuments", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []CollectionOption
+		want time.Duration
+	}{
+		{"default", nil, timeout},
+		{"configured", []CollectionOption{WithTimeout(2 * time.Second)}, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := offlineCollection[bson.M](t, tt.opts...)
+
+			ctx, cancel := c.withTimeout(context.Background())
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if left := time.Until(deadline); !ok || left > tt.want || left < tt.want-time.Second {
+				t.Errorf("deadline in %v, want %v", left, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTimeoutKeepsSoonerDeadline(t *testing.T) {
+	c := offlineCollection[bson.M](t, WithTimeout(time.Minute))
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+
+	ctx, cancel := c.withTimeout(parent)
+	defer cancel()
+
+	want, _ := parent.Deadline()
+	if deadline, _ := ctx.Deadline(); !deadline.Equal(want) {
+		t.Errorf("deadline = %v, want the parent's %v", deadline, want)
+	}
+}
+
+func TestNoTimeout(t *testing.T) {
+	c := offlineCollection[bson.M](t, WithTimeout(NoTimeout))
+
+	ctx, cancel := c.withTimeout(context.Background())
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("NoTimeout context has a deadline")
+	}
+
+	// still cancelled with the returned func
+	cancel()
+	if ctx.Err() == nil {
+		t.Error("NoTimeout context not cancelled by its cancel func")
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[testItem](t)
+
+	id, err := c.Upsert(ctx, bson.M{"name": "a"}, bson.M{"$set": bson.M{"n": 1}})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if _, err = primitive.ObjectIDFromHex(id); err != nil {
+		t.Errorf("Upsert() = %q, want the inserted ObjectID", id)
+	}
+
+	if id, err = c.Upsert(ctx, bson.M{"name": "a"}, bson.M{"$set": bson.M{"n": 2}}); err != nil || id != "" {
+		t.Errorf("Upsert() = %q, %v, want an empty id for an update", id, err)
+	}
+
+	docs, err := c.Find(ctx, nil)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0].N != 2 {
+		t.Errorf("Find() = %+v, want a single document with n 2", docs)
+	}
+}
+
+func TestQueryLogging(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t, WithFilterLogging(FilterLogRedacted))
+
+	var buf bytes.Buffer
+	c.Log = zerolog.New(&buf).Level(zerolog.DebugLevel)
+
+	if _, err := c.FindOne(ctx, bson.M{"email": "alice@example.com"}); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	insertAll(t, c, bson.M{"email": "alice@example.com"})
+	if _, err := c.FindOne(ctx, bson.M{"email": "alice@example.com"}); err != nil {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+
+	var found map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("log line %s: %v", line, err)
+		}
+		if entry["message"] == "document found" {
+			found = entry
+		}
+	}
+	if found == nil {
+		t.Fatalf("no \"document found\" entry in %s", buf.String())
+	}
+	if found["collection"] != "items" || found["took"] == nil {
+		t.Errorf("log entry = %v, want the collection and duration", found)
+	}
+	if found["filter"] != "{email: ?}" {
+		t.Errorf("logged filter = %v, want the redacted {email: ?}", found["filter"])
+	}
+}
+
+func TestFindOptionsComment(t *testing.T) {
+	c := offlineCollection[bson.M](t, WithComment(func(context.Context) string { return "req-1" }))
+
+	opts := options.MergeFindOptions(c.findOptions(context.Background(), []*options.FindOptions{options.Find().SetLimit(1)})...)
+	if opts.Comment == nil || *opts.Comment != "req-1" {
+		t.Errorf("comment = %v, want req-1", opts.Comment)
+	}
+	if opts.Limit == nil || *opts.Limit != 1 {
+		t.Error("caller's limit dropped")
+	}
+
+	opts = options.MergeFindOptions(c.findOptions(context.Background(), []*options.FindOptions{options.Find().SetComment("explicit")})...)
+	if *opts.Comment != "explicit" {
+		t.Errorf("comment = %v, want the explicit one", *opts.Comment)
+	}
+}
+
+func TestCommentOnHelpers(t *testing.T) {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI not set")
+	}
+
+	var (
+		mu       sync.Mutex
+		comments = map[string]string{}
+	)
+	monitor := &event.CommandMonitor{Started: func(_ context.Context, e *event.CommandStartedEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		comments[e.CommandName], _ = e.Command.Lookup("comment").StringValueOK()
+	}}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(monitor))
+	if err != nil {
+		t.Fatalf("mongo.Connect() error = %v", err)
+	}
+	db := client.Database(fmt.Sprintf("test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+
+	c := NewCollection[testItem](db, "items", zerolog.Nop(), WithComment(func(context.Context) string { return "req-1" }))
+	insertAll(t, c, testItem{Name: "a"})
+
+	if _, err = c.NextSequence(ctx, "orders"); err != nil {
+		t.Fatalf("NextSequence() error = %v", err)
+	}
+	if _, err = c.DeleteManyReturning(ctx, bson.M{"name": "a"}); err != nil {
+		t.Fatalf("DeleteManyReturning() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, name := range []string{"findAndModify", "find", "delete"} {
+		if comments[name] != "req-1" {
+			t.Errorf("%s comment = %q, want req-1", name, comments[name])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"sync"
+	"time"
+)
+
+func (c *Collection[T]) Count(ctx context.Context, filter any, opts ...*options.CountOptions) (_ uint64, err error) {
+	ctx, o := c.begin(ctx, "Count")
+	defer func() { o.end(err) }()
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.CountOptions{options.Count().SetComment(comment)}, opts...)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("documents count")
+
+	start := time.Now()
+	n, err := c.Inner.CountDocuments(ctx, filter, opts...)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	o.count(int(n))
+
+	took := time.Since(start)
+	c.debug(ctx).Int64("count", n).Func(c.logFilter(filter)).Dur("took", took).Msg("documents counted")
+
+	c.slowQuery("count", filter, took)
+
+	return uint64(n), nil
+}
+
+// CachedCount returns a func serving the collection's document count from a
+// cache. The first call counts synchronously; once the value is older than
+// refresh, calls keep serving it while a single background count updates it.
+func (c *Collection[T]) CachedCount(refresh time.Duration) func(ctx context.Context) (uint64, error) {
+	var (
+		mu         sync.Mutex
+		count      uint64
+		updatedAt  time.Time
+		refreshing bool
+	)
+
+	return func(ctx context.Context) (uint64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if updatedAt.IsZero() {
+			n, err := c.Count(ctx, nil)
+			if err != nil {
+				return 0, err
+			}
+			count, updatedAt = n, time.Now()
+			return count, nil
+		}
+
+		if !refreshing && time.Since(updatedAt) > refresh {
+			refreshing = true
+			go func() {
+				n, err := c.Count(context.Background(), nil)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				refreshing = false
+				if err != nil {
+					c.Log.Warn().Err(err).Str("collection", c.Inner.Name()).Msg("cached count refresh failed")
+					return
+				}
+				count, updatedAt = n, time.Now()
+			}()
+		}
+
+		return count, nil
+	}
+}
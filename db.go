@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/rs/zerolog"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
 )
 
@@ -12,13 +13,27 @@ const ErrMsgDatabase = "failed to create mongodb database"
 
 var ErrNoDB = errors.New("database name not found in URI")
 
-func GetDB(ctx context.Context, uri string, log zerolog.Logger) *mongo.Database {
-	dbName, err := GetDBName(uri)
+// GetDB is NewDB exiting the process on failure.
+func GetDB(ctx context.Context, uri string, log zerolog.Logger, opts ...*options.ClientOptions) *mongo.Database {
+	db, err := NewDB(ctx, uri, log, opts...)
 	if err != nil {
 		log.Fatal().Err(err).Msg(ErrMsgDatabase)
 	}
+	return db
+}
+
+// NewDB connects to uri and returns the database named in it.
+func NewDB(ctx context.Context, uri string, log zerolog.Logger, opts ...*options.ClientOptions) (*mongo.Database, error) {
+	dbName, err := GetDBName(uri)
+	if err != nil {
+		return nil, err
+	}
 
-	return GetClient(ctx, uri, log).Database(dbName)
+	client, err := NewClient(ctx, uri, log, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return client.Database(dbName), nil
 }
 
 func GetDBName(uri string) (string, error) {
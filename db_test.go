@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+func TestNewClientInvalidURI(t *testing.T) {
+	if _, err := NewClient(context.Background(), "not-a-uri", zerolog.Nop()); err == nil {
+		t.Error("NewClient() error = nil, want an error")
+	}
+}
+
+func TestNewDBInvalidURI(t *testing.T) {
+	if _, err := NewDB(context.Background(), "not-a-uri", zerolog.Nop()); err == nil {
+		t.Error("NewDB() error = nil, want an error")
+	}
+	if _, err := NewDB(context.Background(), "mongodb://127.0.0.1:1", zerolog.Nop()); !errors.Is(err, ErrNoDB) {
+		t.Errorf("NewDB() error = %v, want ErrNoDB", err)
+	}
+}
+
+func TestGetDBName(t *testing.T) {
+	name, err := GetDBName("mongodb://127.0.0.1:27017/cards?retryWrites=true")
+	if err != nil {
+		t.Fatalf("GetDBName() error = %v", err)
+	}
+	if name != "cards" {
+		t.Errorf("GetDBName() = %q, want cards", name)
+	}
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strconv"
+	"time"
+)
+
+const (
+	KeepOldest = "oldest"
+	KeepNewest = "newest"
+
+	dedupeChunkSize = 1000
+)
+
+var ErrInvalidKeep = errors.New("keep must be oldest or newest")
+
+// Deduplicate deletes all but one document per distinct combination of
+// keyFields and returns how many were deleted, e.g. before creating a unique
+// index on them. Which document survives is decided by its _id: the first
+// inserted one for KeepOldest, the last one for KeepNewest.
+func (c *Collection[T]) Deduplicate(ctx context.Context, keyFields []string, keep string) (_ uint64, err error) {
+	ctx, o := c.begin(ctx, "Deduplicate")
+	defer func() { o.end(err) }()
+
+	order := 1
+	switch keep {
+	case KeepOldest:
+	case KeepNewest:
+		order = -1
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidKeep, keep)
+	}
+
+	// keys are positional since dotted field names can't be group keys
+	key := bson.M{}
+	for i, field := range keyFields {
+		key["k"+strconv.Itoa(i)] = "$" + field
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Strs("keys", keyFields).Msg("documents deduplicate")
+
+	start := time.Now()
+	cur, err := c.Inner.Aggregate(ctx, NewPipeline().
+		Sort(bson.D{{Key: "_id", Value: order}}).
+		Group(bson.M{"_id": key, "ids": bson.M{"$push": "$_id"}, "n": bson.M{"$sum": 1}}).
+		Match(bson.M{"n": bson.M{"$gt": 1}}).
+		Build(), c.aggregateOptions(ctx, []*options.AggregateOptions{options.Aggregate().SetAllowDiskUse(true)})...)
+	if err != nil {
+		return 0, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	var (
+		deleted uint64
+		dupes   bson.A
+	)
+	flush := func() error {
+		if len(dupes) == 0 {
+			return nil
+		}
+		result, err := c.Inner.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": dupes}}, c.deleteOptions(ctx, nil)...)
+		if err != nil {
+			return fmt.Errorf(ErrMsgQuery, err)
+		}
+		deleted += uint64(result.DeletedCount)
+		dupes = dupes[:0]
+		return nil
+	}
+
+	for cur.Next(ctx) {
+		var group struct {
+			IDs bson.A `bson:"ids"`
+		}
+		if err = cur.Decode(&group); err != nil {
+			return deleted, fmt.Errorf(ErrMsgDecode, err)
+		}
+
+		dupes = append(dupes, group.IDs[1:]...)
+		if len(dupes) >= dedupeChunkSize {
+			if err = flush(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return deleted, fmt.Errorf(ErrMsgQuery, err)
+	}
+	if err = flush(); err != nil {
+		return deleted, err
+	}
+
+	c.debug(ctx).Uint64("count", deleted).Dur("took", time.Since(start)).Msg("documents deduplicated")
+
+	return deleted, nil
+}
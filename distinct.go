@@ -0,0 +1,70 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const ErrMsgDistinct = "failed to convert distinct value %v due to error: %w"
+
+func (c *Collection[T]) Distinct(ctx context.Context, fieldName string, filter any, opts ...*options.DistinctOptions) (_ []any, err error) {
+	ctx, o := c.begin(ctx, "Distinct")
+	defer func() { o.end(err) }()
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.DistinctOptions{options.Distinct().SetComment(comment)}, opts...)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Str("field", fieldName).Msg("distinct values find")
+
+	start := time.Now()
+	values, err := c.Inner.Distinct(ctx, fieldName, filter, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	if values == nil {
+		values = []any{}
+	}
+	o.count(len(values))
+
+	c.debug(ctx).Str("field", fieldName).Int("count", len(values)).Dur("took", time.Since(start)).Msg("distinct values found")
+
+	return values, nil
+}
+
+// DistinctTyped is Distinct with the values converted to V using the bson
+// decoding rules, so an int32 stored value fits an int or int64 V.
+func DistinctTyped[T, V any](ctx context.Context, c *Collection[T], fieldName string, filter any, opts ...*options.DistinctOptions) ([]V, error) {
+	values, err := c.Distinct(ctx, fieldName, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]V, len(values))
+	for i, value := range values {
+		if v, ok := value.(V); ok {
+			result[i] = v
+			continue
+		}
+
+		t, data, err := bson.MarshalValue(value)
+		if err != nil {
+			return nil, fmt.Errorf(ErrMsgDistinct, value, err)
+		}
+		if err = (bson.RawValue{Type: t, Value: data}).Unmarshal(&result[i]); err != nil {
+			return nil, fmt.Errorf(ErrMsgDistinct, value, err)
+		}
+	}
+	return result, nil
+}
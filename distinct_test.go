@@ -0,0 +1,37 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"sort"
+	"testing"
+)
+
+func TestDistinctTyped(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	insertAll(t, c, bson.M{"tag": "b", "n": 1}, bson.M{"tag": "a", "n": 2}, bson.M{"tag": "b", "n": 3})
+
+	tags, err := DistinctTyped[bson.M, string](ctx, c, "tag", nil)
+	if err != nil {
+		t.Fatalf("DistinctTyped() error = %v", err)
+	}
+	sort.Strings(tags)
+	if len(tags) != 2 || tags[0] != "a" || tags[1] != "b" {
+		t.Errorf("DistinctTyped() = %v, want [a b]", tags)
+	}
+
+	// int32 values decoded into int64
+	ns, err := DistinctTyped[bson.M, int64](ctx, c, "n", bson.M{"tag": "b"})
+	if err != nil {
+		t.Fatalf("DistinctTyped() error = %v", err)
+	}
+	if len(ns) != 2 {
+		t.Errorf("DistinctTyped() = %v, want 2 values", ns)
+	}
+
+	values, err := c.Distinct(ctx, "missing", nil)
+	if err != nil || values == nil || len(values) != 0 {
+		t.Errorf("Distinct() = %#v, %v, want an empty slice", values, err)
+	}
+}
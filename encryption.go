@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ErrMsgEncrypt = "failed to encrypt value due to error: %w"
+	ErrMsgDecrypt = "failed to decrypt value due to error: %w"
+)
+
+var ErrNoEncryption = errors.New("collection has no client encryption configured")
+
+func WithEncryption(ce *mongo.ClientEncryption) CollectionOption {
+	return func(o *collectionOptions) {
+		o.Encryption = ce
+	}
+}
+
+// EncryptField explicitly encrypts value with the data key keyID. Use the
+// deterministic algorithm for values that must support equality queries.
+func (c *Collection[T]) EncryptField(ctx context.Context, value any, keyID primitive.Binary, algorithm string) (_ primitive.Binary, err error) {
+	ctx, o := c.begin(ctx, "EncryptField")
+	defer func() { o.end(err) }()
+
+	if c.Encryption == nil {
+		return primitive.Binary{}, ErrNoEncryption
+	}
+
+	t, data, err := bson.MarshalValue(value)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf(ErrMsgMarshal, err)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	encrypted, err := c.Encryption.Encrypt(
+		ctx,
+		bson.RawValue{Type: t, Value: data},
+		options.Encrypt().SetKeyID(keyID).SetAlgorithm(algorithm),
+	)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf(ErrMsgEncrypt, err)
+	}
+	return encrypted, nil
+}
+
+func (c *Collection[T]) DecryptField(ctx context.Context, value primitive.Binary) (_ bson.RawValue, err error) {
+	ctx, o := c.begin(ctx, "DecryptField")
+	defer func() { o.end(err) }()
+
+	if c.Encryption == nil {
+		return bson.RawValue{}, ErrNoEncryption
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	decrypted, err := c.Encryption.Decrypt(ctx, value)
+	if err != nil {
+		return bson.RawValue{}, fmt.Errorf(ErrMsgDecrypt, err)
+	}
+	return decrypted, nil
+}
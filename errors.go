@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"regexp"
+)
+
+const (
+	duplicateKeyCode     = 11000
+	maxTimeMSExpiredCode = 50
+)
+
+var duplicateKeyIndex = regexp.MustCompile(`index: (\S+)`)
+
+var (
+	ErrPoolExhausted = errors.New("connection pool exhausted")
+	ErrClientTimeout = errors.New("client deadline exceeded")
+	ErrServerTimeout = errors.New("server time limit exceeded")
+)
+
+type timeoutError struct {
+	kind error
+	err  error
+}
+
+// ParseDuplicateKey extracts the violated index name and the conflicting key
+// values from a duplicate key error. Key values are only reported by servers
+// that include keyValue in the error document (4.2+).
+func ParseDuplicateKey(err error) (index string, keyValues bson.M, ok bool) {
+	var (
+		raw bson.Raw
+		msg string
+		we  mongo.WriteException
+		bwe mongo.BulkWriteException
+		ce  mongo.CommandError
+	)
+
+	switch {
+	case errors.As(err, &we):
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				raw, msg, ok = e.Raw, e.Message, true
+				break
+			}
+		}
+	case errors.As(err, &bwe):
+		for _, e := range bwe.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				raw, msg, ok = e.Raw, e.Message, true
+				break
+			}
+		}
+	case errors.As(err, &ce):
+		if ce.Code == duplicateKeyCode {
+			raw, msg, ok = ce.Raw, ce.Message, true
+		}
+	}
+	if !ok {
+		return "", nil, false
+	}
+
+	if m := duplicateKeyIndex.FindStringSubmatch(msg); m != nil {
+		index = m[1]
+	}
+	if doc, found := raw.Lookup("keyValue").DocumentOK(); found {
+		_ = bson.Unmarshal(doc, &keyValues)
+	}
+
+	return index, keyValues, true
+}
+
+// IsPoolExhausted reports whether err was caused by waiting too long for a
+// free connection. The driver bounds that wait only by the operation
+// context, never by a shorter checkout timeout of its own; see NewClient.
+func IsPoolExhausted(err error) bool {
+	var wqErr topology.WaitQueueTimeoutError
+	return errors.Is(err, ErrPoolExhausted) || errors.As(err, &wqErr)
+}
+
+// ClassifyTimeout tells apart the two kinds of timeouts: err is returned
+// additionally matching ErrServerTimeout when the server aborted the
+// operation for exceeding maxTimeMS, which is worth retrying, or
+// ErrClientTimeout when the client deadline elapsed or the network timed
+// out, which is not. Other errors are returned as is.
+func ClassifyTimeout(err error) error {
+	var se mongo.ServerError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrServerTimeout) || errors.Is(err, ErrClientTimeout):
+		return err
+	case errors.As(err, &se) && se.HasErrorCode(maxTimeMSExpiredCode):
+		return &timeoutError{kind: ErrServerTimeout, err: err}
+	case errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err):
+		return &timeoutError{kind: ErrClientTimeout, err: err}
+	}
+	return err
+}
+
+func (e *timeoutError) Error() string {
+	return e.err.Error()
+}
+
+func (e *timeoutError) Unwrap() error {
+	return e.err
+}
+
+func (e *timeoutError) Is(target error) bool {
+	return target == e.kind
+}
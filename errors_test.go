@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"testing"
+)
+
+func TestClassifyTimeout(t *testing.T) {
+	serverTimeout := mongo.CommandError{Code: maxTimeMSExpiredCode, Name: "MaxTimeMSExpired"}
+
+	tests := []struct {
+		name           string
+		err            error
+		server, client bool
+	}{
+		{"max time expired", fmt.Errorf(ErrMsgQuery, serverTimeout), true, false},
+		{"deadline", fmt.Errorf(ErrMsgQuery, context.DeadlineExceeded), false, true},
+		{"other", errors.New("boom"), false, false},
+		{"nil", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyTimeout(tt.err)
+			if errors.Is(got, ErrServerTimeout) != tt.server || errors.Is(got, ErrClientTimeout) != tt.client {
+				t.Errorf("ClassifyTimeout() = %v, want server %v and client %v", got, tt.server, tt.client)
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Errorf("ClassifyTimeout() = %v, lost the original error", got)
+			}
+		})
+	}
+
+	once := ClassifyTimeout(fmt.Errorf(ErrMsgQuery, serverTimeout))
+	if ClassifyTimeout(once) != once {
+		t.Error("ClassifyTimeout() wrapped an already classified error again")
+	}
+}
+
+func TestIsPoolExhausted(t *testing.T) {
+	checkout := topology.WaitQueueTimeoutError{Wrapped: context.DeadlineExceeded}
+
+	if !IsPoolExhausted(fmt.Errorf(ErrMsgQuery, checkout)) {
+		t.Error("IsPoolExhausted() = false for a timed out checkout")
+	}
+	if !IsPoolExhausted(fmt.Errorf("find: %w", ErrPoolExhausted)) {
+		t.Error("IsPoolExhausted() = false for ErrPoolExhausted")
+	}
+	if IsPoolExhausted(fmt.Errorf(ErrMsgQuery, context.DeadlineExceeded)) {
+		t.Error("IsPoolExhausted() = true for a plain deadline")
+	}
+}
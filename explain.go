@@ -0,0 +1,173 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"strings"
+	"time"
+)
+
+const (
+	ExplainQueryPlanner    = "queryPlanner"
+	ExplainExecutionStats  = "executionStats"
+	ExplainAllPlansExecute = "allPlansExecution"
+)
+
+var (
+	ErrNoExecutionStats = errors.New("execution stats not found in explain output")
+	ErrCollScan         = errors.New("query requires a collection scan")
+)
+
+// Explain returns the server's explain output for a find with filter.
+func (c *Collection[T]) Explain(ctx context.Context, filter any, verbosity string) (_ bson.Raw, err error) {
+	ctx, o := c.begin(ctx, "Explain")
+	defer func() { o.end(err) }()
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.explain(ctx, bson.D{{Key: "find", Value: c.Inner.Name()}, {Key: "filter", Value: filter}}, verbosity)
+}
+
+// ExecutionTime executes filter again under explain and returns the time
+// the server spent on that run, excluding network and client overhead. It
+// is a separate execution, not the timing of an earlier operation, so it
+// costs a full query and may differ from it, e.g. due to a warmer cache.
+func (c *Collection[T]) ExecutionTime(ctx context.Context, filter any) (_ time.Duration, err error) {
+	ctx, o := c.begin(ctx, "ExecutionTime")
+	defer func() { o.end(err) }()
+
+	raw, err := c.Explain(ctx, filter, ExplainExecutionStats)
+	if err != nil {
+		return 0, err
+	}
+
+	ms, ok := raw.Lookup("executionStats", "executionTimeMillis").AsInt64OK()
+	if !ok {
+		return 0, ErrNoExecutionStats
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// RequireIndex returns ErrCollScan if the planner would answer filter with
+// a collection scan. Use it to guard large UpdateMany/DeleteMany calls; hint
+// is optional and should match the hint passed to the guarded operation.
+func (c *Collection[T]) RequireIndex(ctx context.Context, filter any, hint any) (err error) {
+	ctx, o := c.begin(ctx, "RequireIndex")
+	defer func() { o.end(err) }()
+
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	cmd := bson.D{{Key: "find", Value: c.Inner.Name()}, {Key: "filter", Value: filter}}
+	if hint != nil {
+		cmd = append(cmd, bson.E{Key: "hint", Value: hint})
+	}
+
+	raw, err := c.explain(ctx, cmd, ExplainQueryPlanner)
+	if err != nil {
+		return err
+	}
+
+	if hasStage(raw.Lookup("queryPlanner", "winningPlan"), "COLLSCAN") {
+		return ErrCollScan
+	}
+	return nil
+}
+
+func (c *Collection[T]) explain(ctx context.Context, cmd bson.D, verbosity string) (bson.Raw, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	explain := bson.D{{Key: "explain", Value: cmd}, {Key: "verbosity", Value: verbosity}}
+	if comment, ok := c.comment(ctx); ok {
+		explain = append(explain, bson.E{Key: "comment", Value: comment})
+	}
+
+	raw, err := c.Inner.Database().RunCommand(ctx, explain).DecodeBytes()
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	return raw, nil
+}
+
+// explainSlow logs the winning plan of an operation that exceeded the slow
+// threshold. It runs in the background so the caller doesn't pay for it.
+func (c *Collection[T]) explainSlow(op string, filter any, took time.Duration) {
+	if !c.ExplainSlow || c.SlowThreshold <= 0 || took < c.SlowThreshold {
+		return
+	}
+
+	// sessions can't be shared with a concurrent goroutine
+	unbound := *c
+	unbound.session = nil
+
+	go func() {
+		raw, err := unbound.explain(
+			context.Background(),
+			bson.D{{Key: "find", Value: c.Inner.Name()}, {Key: "filter", Value: filter}},
+			ExplainQueryPlanner,
+		)
+		if err != nil {
+			c.Log.Warn().Err(err).Str("collection", c.Inner.Name()).Str("operation", op).Dur("took", took).Msg("slow query explain failed")
+			return
+		}
+
+		plan := raw.Lookup("queryPlanner", "winningPlan")
+
+		c.Log.Warn().
+			Str("collection", c.Inner.Name()).
+			Str("operation", op).
+			Dur("took", took).
+			Str("plan", planSummary(plan)).
+			Bool("indexed", !hasStage(plan, "COLLSCAN")).
+			Msg("slow query")
+	}()
+}
+
+// planSummary renders the stages of a plan from the top, e.g.
+// "FETCH > IXSCAN email_1".
+func planSummary(plan bson.RawValue) string {
+	var stages []string
+	for doc, ok := plan.DocumentOK(); ok; doc, ok = doc.Lookup("inputStage").DocumentOK() {
+		stage, _ := doc.Lookup("stage").StringValueOK()
+		if index, ok := doc.Lookup("indexName").StringValueOK(); ok {
+			stage += " " + index
+		}
+		stages = append(stages, stage)
+	}
+	return strings.Join(stages, " > ")
+}
+
+// hasStage searches a plan tree, including sharded and multi-input plans.
+func hasStage(plan bson.RawValue, stage string) bool {
+	var values []bson.RawValue
+
+	switch plan.Type {
+	case bsontype.EmbeddedDocument:
+		doc := plan.Document()
+		if s, ok := doc.Lookup("stage").StringValueOK(); ok && s == stage {
+			return true
+		}
+		elems, _ := doc.Elements()
+		for _, e := range elems {
+			values = append(values, e.Value())
+		}
+	case bsontype.Array:
+		values, _ = plan.Array().Values()
+	}
+
+	for _, v := range values {
+		if hasStage(v, stage) {
+			return true
+		}
+	}
+	return false
+}
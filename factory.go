@@ -0,0 +1,25 @@
+package mongodb
+
+import (
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Factory struct {
+	DB          *mongo.Database
+	Log         zerolog.Logger
+	DefaultOpts []CollectionOption
+}
+
+func NewFactory(db *mongo.Database, log zerolog.Logger, opts ...CollectionOption) *Factory {
+	return &Factory{DB: db, Log: log, DefaultOpts: opts}
+}
+
+// FactoryCollection creates a collection with the factory defaults applied
+// first, so opts can override them per collection.
+func FactoryCollection[T any](f *Factory, name string, opts ...CollectionOption) *Collection[T] {
+	all := make([]CollectionOption, 0, len(f.DefaultOpts)+len(opts))
+	all = append(all, f.DefaultOpts...)
+	all = append(all, opts...)
+	return NewCollection[T](f.DB, name, f.Log, all...)
+}
@@ -3,6 +3,7 @@ package mongodb
 import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"regexp"
 	"strings"
 )
 
@@ -119,6 +120,12 @@ func Regex(name string, pattern string, opts ...string) *field {
 	}}
 }
 
+// RegexLiteral matches literal as a plain substring, escaping any regex
+// metacharacters so user input can't change the pattern.
+func RegexLiteral(name string, literal string, opts ...string) *field {
+	return Regex(name, regexp.QuoteMeta(literal), opts...)
+}
+
 func All(name string, value ...any) *field {
 	return &field{name: name, op: all, value: value}
 }
@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+func TestNormalizeFilterIDs(t *testing.T) {
+	id1, id2 := primitive.NewObjectID(), primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		filter any
+		want   string
+	}{
+		{"hex list", []string{id1.Hex(), id2.Hex()}, `{"_id":{"$in":[{"$oid":"` + id1.Hex() + `"},{"$oid":"` + id2.Hex() + `"}]}}`},
+		{"empty hex list", []string{}, `{"_id":{"$in":[]}}`},
+		{"nil ObjectID list", []primitive.ObjectID(nil), `{"_id":{"$in":[]}}`},
+		{"ObjectID list", []primitive.ObjectID{id1}, `{"_id":{"$in":[{"$oid":"` + id1.Hex() + `"}]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NormalizeFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("NormalizeFilter() error = %v", err)
+			}
+			got, err := bson.MarshalExtJSON(filter, false, false)
+			if err != nil {
+				t.Fatalf("MarshalExtJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("NormalizeFilter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeFilterInvalidHex(t *testing.T) {
+	for _, filter := range []any{"not-an-id", []string{primitive.NewObjectID().Hex(), "not-an-id"}} {
+		if _, err := NormalizeFilter(filter); !errors.Is(err, primitive.ErrInvalidHex) {
+			t.Errorf("NormalizeFilter(%v) error = %v, want ErrInvalidHex", filter, err)
+		}
+	}
+}
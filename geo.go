@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidPolygon = errors.New("invalid polygon")
+
+// FindWithin returns the documents whose GeoJSON field lies inside polygon.
+// Points are [longitude, latitude] pairs; the ring is closed if the last
+// point doesn't repeat the first.
+func (c *Collection[T]) FindWithin(ctx context.Context, field string, polygon [][]float64, opts ...*options.FindOptions) ([]T, error) {
+	geometry, err := PolygonGeometry(polygon)
+	if err != nil {
+		return nil, err
+	}
+	return c.Find(ctx, bson.M{field: bson.M{"$geoWithin": bson.M{"$geometry": geometry}}}, opts...)
+}
+
+// PolygonGeometry builds a GeoJSON polygon from a single ring of
+// [longitude, latitude] points, validating and closing it.
+func PolygonGeometry(polygon [][]float64) (bson.M, error) {
+	ring := make(bson.A, 0, len(polygon)+1)
+	for i, p := range polygon {
+		if len(p) != 2 {
+			return nil, fmt.Errorf("%w: point %d must be [longitude, latitude]", ErrInvalidPolygon, i)
+		}
+		if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+			return nil, fmt.Errorf("%w: point %d is out of range, longitude goes first", ErrInvalidPolygon, i)
+		}
+		ring = append(ring, bson.A{p[0], p[1]})
+	}
+
+	if n := len(polygon); n > 0 && (polygon[0][0] != polygon[n-1][0] || polygon[0][1] != polygon[n-1][1]) {
+		ring = append(ring, ring[0])
+	}
+	if len(ring) < 4 {
+		return nil, fmt.Errorf("%w: a ring needs at least 3 distinct points", ErrInvalidPolygon)
+	}
+
+	return bson.M{"type": "Polygon", "coordinates": bson.A{ring}}, nil
+}
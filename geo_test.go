@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+	"testing"
+)
+
+func TestPolygonGeometry(t *testing.T) {
+	square := [][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	want := bson.M{"type": "Polygon", "coordinates": bson.A{bson.A{
+		bson.A{0.0, 0.0}, bson.A{1.0, 0.0}, bson.A{1.0, 1.0}, bson.A{0.0, 1.0}, bson.A{0.0, 0.0},
+	}}}
+
+	got, err := PolygonGeometry(square)
+	if err != nil {
+		t.Fatalf("PolygonGeometry() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PolygonGeometry() = %v, want %v", got, want)
+	}
+
+	// an already closed ring isn't closed twice
+	if got, err = PolygonGeometry(append(square, []float64{0, 0})); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("PolygonGeometry() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestPolygonGeometryInvalid(t *testing.T) {
+	tests := map[string][][]float64{
+		"too few points":      {{0, 0}, {1, 1}},
+		"two distinct points": {{0, 0}, {1, 1}, {0, 0}},
+		"bad point":           {{0, 0}, {1}, {1, 1}},
+		"latitude first":      {{10, 100}, {11, 100}, {11, 101}},
+		"empty":               nil,
+	}
+	for name, polygon := range tests {
+		if _, err := PolygonGeometry(polygon); !errors.Is(err, ErrInvalidPolygon) {
+			t.Errorf("%s: PolygonGeometry() error = %v, want ErrInvalidPolygon", name, err)
+		}
+	}
+}
+
+func TestFindWithin(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	if _, err := c.CreateIndexes(ctx, []mongo.IndexModel{{Keys: bson.D{{Key: "loc", Value: "2dsphere"}}}}); err != nil {
+		t.Fatalf("CreateIndexes() error = %v", err)
+	}
+	insertAll(t, c,
+		bson.M{"name": "inside", "loc": bson.M{"type": "Point", "coordinates": bson.A{0.5, 0.5}}},
+		bson.M{"name": "outside", "loc": bson.M{"type": "Point", "coordinates": bson.A{2.0, 2.0}}},
+	)
+
+	docs, err := c.FindWithin(ctx, "loc", [][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}})
+	if err != nil {
+		t.Fatalf("FindWithin() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0]["name"] != "inside" {
+		t.Errorf("FindWithin() = %v, want only the inside point", docs)
+	}
+}
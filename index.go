@@ -0,0 +1,186 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const indexOptionsConflictCode = 85
+
+type IndexSpec struct {
+	Name   string `bson:"name"`
+	Key    bson.D `bson:"key"`
+	Unique bool   `bson:"unique"`
+}
+
+func (c *Collection[T]) CreateIndexes(ctx context.Context, models []mongo.IndexModel, opts ...*options.CreateIndexesOptions) (_ []string, err error) {
+	ctx, o := c.begin(ctx, "CreateIndexes")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Int("count", len(models)).Msg("indexes create")
+
+	start := time.Now()
+	names, err := c.Inner.Indexes().CreateMany(ctx, models, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Strs("names", names).Dur("took", time.Since(start)).Msg("indexes created")
+
+	return names, nil
+}
+
+// EnsureIndexes creates the indexes one by one, skipping those whose keys
+// are already indexed, so it is safe to call on every startup. An existing
+// index on the same keys is kept even if its name or options differ.
+func (c *Collection[T]) EnsureIndexes(ctx context.Context, models []mongo.IndexModel) error {
+	for _, model := range models {
+		_, err := c.CreateIndexes(ctx, []mongo.IndexModel{model})
+		var se mongo.ServerError
+		if err != nil && !(errors.As(err, &se) && se.HasErrorCode(indexOptionsConflictCode)) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropIndex drops the index called name. Dropping a missing index fails
+// with the server's IndexNotFound error, reachable via mongo.ServerError.
+func (c *Collection[T]) DropIndex(ctx context.Context, name string) (err error) {
+	ctx, o := c.begin(ctx, "DropIndex")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Str("name", name).Msg("index drop")
+
+	start := time.Now()
+	if _, err := c.Inner.Indexes().DropOne(ctx, name); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Str("name", name).Dur("took", time.Since(start)).Msg("index dropped")
+
+	return nil
+}
+
+// DropAllIndexes drops every index but the _id one.
+func (c *Collection[T]) DropAllIndexes(ctx context.Context) (err error) {
+	ctx, o := c.begin(ctx, "DropAllIndexes")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("indexes drop")
+
+	start := time.Now()
+	if _, err := c.Inner.Indexes().DropAll(ctx); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Dur("took", time.Since(start)).Msg("indexes dropped")
+
+	return nil
+}
+
+// ListIndexes returns the raw specifications of the collection's indexes,
+// including the default _id_ index.
+func (c *Collection[T]) ListIndexes(ctx context.Context) ([]bson.M, error) {
+	return listIndexes[T, bson.M](ctx, c, "ListIndexes")
+}
+
+func (c *Collection[T]) IndexSpecs(ctx context.Context) ([]IndexSpec, error) {
+	return listIndexes[T, IndexSpec](ctx, c, "IndexSpecs")
+}
+
+func listIndexes[T, S any](ctx context.Context, c *Collection[T], op string) (_ []S, err error) {
+	ctx, o := c.begin(ctx, op)
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("indexes list")
+
+	start := time.Now()
+	cur, err := c.Inner.Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	specs, err := DecodeAll[S](ctx, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	c.debug(ctx).Int("count", len(specs)).Dur("took", time.Since(start)).Msg("indexes listed")
+
+	return specs, nil
+}
+
+func (c *Collection[T]) HasIndex(ctx context.Context, keys bson.D) (_ bool, err error) {
+	ctx, o := c.begin(ctx, "HasIndex")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	cur, err := c.Inner.Indexes().List(ctx)
+	if err != nil {
+		return false, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(ctx) {
+		var spec struct {
+			Key bson.D `bson:"key"`
+		}
+		if err = cur.Decode(&spec); err != nil {
+			return false, fmt.Errorf(ErrMsgDecode, err)
+		}
+		if sameIndexKeys(spec.Key, keys) {
+			return true, nil
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return false, fmt.Errorf(ErrMsgQuery, err)
+	}
+	return false, nil
+}
+
+// sameIndexKeys compares key specifications in order, treating numeric
+// directions of different types (1, int32(1), 1.0) as equal.
+func sameIndexKeys(a, b bson.D) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || indexKeyValue(a[i].Value) != indexKeyValue(b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func indexKeyValue(v any) any {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return v
+}
@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"reflect"
+	"testing"
+)
+
+func TestSameIndexKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b bson.D
+		want bool
+	}{
+		{"numeric types", bson.D{{Key: "a", Value: 1}, {Key: "b", Value: int64(-1)}}, bson.D{{Key: "a", Value: int32(1)}, {Key: "b", Value: -1.0}}, true},
+		{"text", bson.D{{Key: "title", Value: "text"}}, bson.D{{Key: "title", Value: "text"}}, true},
+		{"order", bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 1}}, bson.D{{Key: "b", Value: 1}, {Key: "a", Value: 1}}, false},
+		{"direction", bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "a", Value: -1}}, false},
+		{"prefix", bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameIndexKeys(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameIndexKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateIndexes(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	names, err := c.CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateIndexes() error = %v", err)
+	}
+	if want := []string{"email_1", "createdAt_-1"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("CreateIndexes() = %v, want %v", names, want)
+	}
+
+	insertAll(t, c, bson.M{"email": "a@example.com"})
+	if _, err = c.InsertOne(ctx, bson.M{"email": "a@example.com"}); !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("InsertOne() error = %v, want a duplicate key error", err)
+	}
+}
+
+func TestIndexSpecs(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	if _, err := c.CreateIndexes(ctx, []mongo.IndexModel{{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)}}); err != nil {
+		t.Fatalf("CreateIndexes() error = %v", err)
+	}
+
+	specs, err := c.IndexSpecs(ctx)
+	if err != nil {
+		t.Fatalf("IndexSpecs() error = %v", err)
+	}
+	found := map[string]IndexSpec{}
+	for _, spec := range specs {
+		found[spec.Name] = spec
+	}
+	if _, ok := found["_id_"]; !ok {
+		t.Errorf("IndexSpecs() = %+v, missing the _id_ index", specs)
+	}
+	if spec := found["email_1"]; !spec.Unique || !sameIndexKeys(spec.Key, bson.D{{Key: "email", Value: 1}}) {
+		t.Errorf("IndexSpecs() email_1 = %+v, want a unique index on email", spec)
+	}
+
+	raw, err := c.ListIndexes(ctx)
+	if err != nil || len(raw) != len(specs) {
+		t.Errorf("ListIndexes() = %d indexes, %v, want %d", len(raw), err, len(specs))
+	}
+}
+
+func TestDropIndexes(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	if _, err := c.CreateIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "a", Value: 1}}},
+		{Keys: bson.D{{Key: "b", Value: 1}}},
+	}); err != nil {
+		t.Fatalf("CreateIndexes() error = %v", err)
+	}
+
+	if err := c.DropIndex(ctx, "a_1"); err != nil {
+		t.Fatalf("DropIndex() error = %v", err)
+	}
+	if ok, err := c.HasIndex(ctx, bson.D{{Key: "a", Value: 1}}); err != nil || ok {
+		t.Errorf("HasIndex() = %v, %v, want the dropped index gone", ok, err)
+	}
+
+	var se mongo.ServerError
+	if err := c.DropIndex(ctx, "a_1"); !errors.As(err, &se) {
+		t.Errorf("DropIndex() error = %v, want a server error for a missing index", err)
+	}
+
+	if err := c.DropAllIndexes(ctx); err != nil {
+		t.Fatalf("DropAllIndexes() error = %v", err)
+	}
+	specs, err := c.IndexSpecs(ctx)
+	if err != nil || len(specs) != 1 || specs[0].Name != "_id_" {
+		t.Errorf("IndexSpecs() = %+v, %v, want only _id_", specs, err)
+	}
+}
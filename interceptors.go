@@ -4,11 +4,28 @@ import (
 	"context"
 	"errors"
 	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// errorDomain marks the ErrorInfo details the interceptors attach, so that
+// FromStatus only restores errors it can tell apart from other sources of
+// the same status code.
+const (
+	errorDomain         = "mongodb"
+	reasonPoolExhausted = "POOL_EXHAUSTED"
+	reasonServerTimeout = "SERVER_TIMEOUT"
+)
+
+var ErrDuplicateKey = errors.New("duplicate key")
+
+type statusError struct {
+	st  *status.Status
+	err error
+}
+
 func ErrorUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		res, err := handler(ctx, req)
@@ -31,9 +48,13 @@ func normalizeError(err error) error {
 	if _, ok := status.FromError(err); !ok {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			err = status.Error(codes.NotFound, err.Error())
+		} else if IsPoolExhausted(err) {
+			err = reasonError(codes.ResourceExhausted, err.Error(), reasonPoolExhausted)
 		} else if mongo.IsDuplicateKeyError(err) {
 			err = status.Error(codes.AlreadyExists, err.Error())
-		} else if mongo.IsTimeout(err) {
+		} else if errors.Is(ClassifyTimeout(err), ErrServerTimeout) {
+			err = reasonError(codes.Unavailable, err.Error(), reasonServerTimeout)
+		} else if errors.Is(ClassifyTimeout(err), ErrClientTimeout) {
 			err = status.Error(codes.DeadlineExceeded, err.Error())
 		} else {
 			err = status.Error(codes.Internal, err.Error())
@@ -42,3 +63,66 @@ func normalizeError(err error) error {
 
 	return err
 }
+
+// FromStatus is the inverse of the server interceptors: it restores the
+// sentinel behind a gRPC status so errors.Is works on the client side.
+func FromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		return &statusError{st: st, err: mongo.ErrNoDocuments}
+	case codes.AlreadyExists:
+		return &statusError{st: st, err: ErrDuplicateKey}
+	case codes.DeadlineExceeded:
+		return &statusError{st: st, err: &timeoutError{kind: ErrClientTimeout, err: context.DeadlineExceeded}}
+	case codes.Unavailable:
+		// also used for unreachable servers and refused connections
+		if hasReason(st, reasonServerTimeout) {
+			return &statusError{st: st, err: ErrServerTimeout}
+		}
+	case codes.ResourceExhausted:
+		// also used by gRPC itself, e.g. for oversized messages
+		if hasReason(st, reasonPoolExhausted) {
+			return &statusError{st: st, err: ErrPoolExhausted}
+		}
+	}
+
+	return err
+}
+
+func reasonError(code codes.Code, msg string, reason string) error {
+	st := status.New(code, msg)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func hasReason(st *status.Status, reason string) bool {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == errorDomain && info.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *statusError) Error() string {
+	return e.st.Message()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.st
+}
@@ -0,0 +1,40 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestFromStatusPoolExhausted(t *testing.T) {
+	err := FromStatus(normalizeError(fmt.Errorf("find: %w", ErrPoolExhausted)))
+	if !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("FromStatus() = %v, want ErrPoolExhausted", err)
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("status code = %v, want ResourceExhausted", status.Code(err))
+	}
+}
+
+func TestFromStatusResourceExhaustedUnmarked(t *testing.T) {
+	in := status.Error(codes.ResourceExhausted, "grpc: received message larger than max")
+	if err := FromStatus(in); err != in || errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("FromStatus() = %v, want the status unchanged", err)
+	}
+}
+
+func TestFromStatusServerTimeout(t *testing.T) {
+	err := FromStatus(normalizeError(&timeoutError{kind: ErrServerTimeout, err: errors.New("operation exceeded time limit")}))
+	if !errors.Is(err, ErrServerTimeout) {
+		t.Fatalf("FromStatus() = %v, want ErrServerTimeout", err)
+	}
+}
+
+func TestFromStatusUnavailableUnmarked(t *testing.T) {
+	in := status.Error(codes.Unavailable, "connection refused")
+	if err := FromStatus(in); err != in || errors.Is(err, ErrServerTimeout) {
+		t.Fatalf("FromStatus() = %v, want the status unchanged", err)
+	}
+}
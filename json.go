@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
+)
+
+// FindJSONReader streams matching documents as a relaxed extended JSON array.
+// Documents are encoded only as the reader consumes them; ctx bounds the
+// whole stream and closing the reader stops it early.
+func (c *Collection[T]) FindJSONReader(ctx context.Context, filter any, opts ...*options.FindOptions) (io.ReadCloser, error) {
+	// the operation ends with the stream, not with this call
+	ctx, o := c.begin(ctx, "FindJSONReader")
+
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		o.end(err)
+		return nil, err
+	}
+
+	findCtx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.debug(ctx).Msg("documents stream")
+
+	cur, err := c.Inner.Find(findCtx, filter, c.findOptions(ctx, opts)...)
+	if err != nil {
+		err = fmt.Errorf(ErrMsgQuery, err)
+		o.end(err)
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer cur.Close(context.Background())
+		err := writeJSONArray(ctx, cur, pw)
+		// a reader closed early is a normal end of the stream
+		if errors.Is(err, io.ErrClosedPipe) {
+			o.end(nil)
+		} else {
+			o.end(err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+func writeJSONArray(ctx context.Context, cur *mongo.Cursor, w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i := 0; cur.Next(ctx); i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		data, err := bson.MarshalExtJSON(cur.Current, false, false)
+		if err != nil {
+			return fmt.Errorf(ErrMsgMarshal, err)
+		}
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := cur.Err(); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	_, err := io.WriteString(w, "]")
+	return err
+}
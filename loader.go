@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"sync"
+	"time"
+)
+
+// Loader batches Load calls made within wait of each other into a single
+// $in query and caches what it loaded. It is meant to live for a single
+// request, since cached documents are never refreshed.
+type Loader[T any] struct {
+	coll  *Collection[T]
+	wait  time.Duration
+	mu    sync.Mutex
+	cache map[string]T
+	batch *loaderBatch[T]
+}
+
+type loaderBatch[T any] struct {
+	ids  []string
+	seen map[string]bool
+	done chan struct{}
+	docs map[string]T
+	err  error
+}
+
+func NewLoader[T any](c *Collection[T], wait time.Duration) *Loader[T] {
+	return &Loader[T]{coll: c, wait: wait, cache: map[string]T{}}
+}
+
+// Load returns the document with the given hex id, or mongo.ErrNoDocuments.
+func (l *Loader[T]) Load(ctx context.Context, id string) (doc T, err error) {
+	// reject bad ids here, so they can't fail the whole batch
+	if _, err = primitive.ObjectIDFromHex(id); err != nil {
+		return doc, fmt.Errorf(ErrMsgFromHex, err)
+	}
+
+	l.mu.Lock()
+	if cached, ok := l.cache[id]; ok {
+		l.mu.Unlock()
+		return cached, nil
+	}
+
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[T]{seen: map[string]bool{}, done: make(chan struct{})}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+	if !b.seen[id] {
+		b.seen[id] = true
+		b.ids = append(b.ids, id)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return doc, ctx.Err()
+	}
+
+	if b.err != nil {
+		return doc, b.err
+	}
+	if doc, ok := b.docs[id]; ok {
+		return doc, nil
+	}
+	return doc, mongo.ErrNoDocuments
+}
+
+func (l *Loader[T]) dispatch(b *loaderBatch[T]) {
+	l.mu.Lock()
+	l.batch = nil
+	l.mu.Unlock()
+
+	b.docs, b.err = l.coll.FindByIDsMap(context.Background(), b.ids)
+
+	if b.err == nil {
+		l.mu.Lock()
+		for id, doc := range b.docs {
+			l.cache[id] = doc
+		}
+		l.mu.Unlock()
+	}
+
+	close(b.done)
+}
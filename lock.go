@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// FindAndLock writes a fresh value to lockField of the matched document
+// inside the caller's transaction. Any other transaction touching the same
+// document then hits a write conflict until this one commits or aborts.
+func (c *Collection[T]) FindAndLock(sc mongo.SessionContext, filter any, lockField string) (T, error) {
+	return c.FindOneAndUpdate(
+		sc,
+		filter,
+		bson.M{"$set": bson.M{lockField: primitive.NewObjectID()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+}
+
+// AcquireLock takes the lock document key for owner until ttl elapses. It
+// succeeds if the lock is free, expired or already held by owner (which
+// extends it), and returns false if another owner holds it. Expiry uses the
+// local clock, so instances sharing a lock need reasonably synced clocks.
+func (c *Collection[T]) AcquireLock(ctx context.Context, key string, owner string, ttl time.Duration) (_ bool, err error) {
+	ctx, o := c.begin(ctx, "AcquireLock")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now()
+
+	_, err = c.Inner.UpdateOne(
+		ctx,
+		bson.M{"_id": key, "$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$lte": now}},
+			bson.M{"owner": owner},
+		}},
+		bson.M{"$set": bson.M{"owner": owner, "expiresAt": now.Add(ttl)}},
+		c.updateOptions(ctx, []*options.UpdateOptions{options.Update().SetUpsert(true)})...,
+	)
+	// the lock exists and is held by someone else, so the upsert collided
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Str("key", key).Str("owner", owner).Msg("lock acquired")
+
+	return true, nil
+}
+
+// ReleaseLock releases key if owner still holds it; releasing a lock that
+// expired or was taken over is a no-op.
+func (c *Collection[T]) ReleaseLock(ctx context.Context, key string, owner string) (err error) {
+	ctx, o := c.begin(ctx, "ReleaseLock")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := c.Inner.DeleteOne(ctx, bson.M{"_id": key, "owner": owner}, c.deleteOptions(ctx, nil)...); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Str("key", key).Str("owner", owner).Msg("lock released")
+
+	return nil
+}
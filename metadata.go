@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"sort"
+	"strings"
+)
+
+type (
+	metadataKey struct{}
+
+	metadataStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+)
+
+// MetadataUnaryServerInterceptor copies the given incoming gRPC metadata
+// keys, e.g. tenant or user ids, into the context, where collections add
+// them to their debug logs and MetadataComment turns them into an operation
+// comment.
+func MetadataUnaryServerInterceptor(keys ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withIncomingMetadata(ctx, keys), req)
+	}
+}
+
+func MetadataStreamServerInterceptor(keys ...string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &metadataStream{ServerStream: stream, ctx: withIncomingMetadata(stream.Context(), keys)})
+	}
+}
+
+// RequestMetadata returns the metadata stashed in ctx by the metadata
+// interceptors.
+func RequestMetadata(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return md
+}
+
+// MetadataComment is a comment hook for WithComment formatting the request
+// metadata as sorted key=value pairs.
+func MetadataComment(ctx context.Context) string {
+	md := RequestMetadata(ctx)
+	pairs := make([]string, 0, len(md))
+	for _, k := range sortedKeys(md) {
+		pairs = append(pairs, k+"="+md[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func withIncomingMetadata(ctx context.Context, keys []string) context.Context {
+	in, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	md := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v := in.Get(k); len(v) > 0 {
+			md[k] = strings.Join(v, " ")
+		}
+	}
+	if len(md) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+func sortedKeys(md map[string]string) []string {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *metadataStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,11 @@
+module github.com/go-funcards/mongodb/metrics/prometheus
+
+go 1.18
+
+require (
+	github.com/go-funcards/mongodb v0.0.0
+	github.com/prometheus/client_golang v1.12.2
+	go.mongodb.org/mongo-driver v1.10.0
+)
+
+replace github.com/go-funcards/mongodb => ../..
@@ -0,0 +1,43 @@
+// Package prometheus reports collection operations to Prometheus. It lives
+// in a module of its own so the mongodb module stays free of the client
+// library.
+package prometheus
+
+import (
+	"github.com/go-funcards/mongodb"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Metrics implements mongodb.Metrics with a mongodb_operations_total
+// counter labeled by op, collection and mongodb.OperationResult, and a
+// mongodb_operation_duration_seconds histogram labeled by op and collection.
+type Metrics struct {
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+var _ mongodb.Metrics = (*Metrics)(nil)
+
+// New registers the collectors with reg, e.g. prometheus.DefaultRegisterer,
+// and panics if they are already registered there.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_operations_total",
+			Help: "Number of collection operations by result.",
+		}, []string{"op", "collection", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mongodb_operation_duration_seconds",
+			Help:    "Duration of collection operations.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"op", "collection"}),
+	}
+	reg.MustRegister(m.operations, m.duration)
+	return m
+}
+
+func (m *Metrics) ObserveOperation(op string, collection string, took time.Duration, err error) {
+	m.operations.WithLabelValues(op, collection, mongodb.OperationResult(err)).Inc()
+	m.duration.WithLabelValues(op, collection).Observe(took.Seconds())
+}
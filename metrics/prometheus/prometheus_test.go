@@ -0,0 +1,56 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+	"time"
+)
+
+func TestObserveOperation(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+
+	m.ObserveOperation("FindOne", "items", 10*time.Millisecond, nil)
+	m.ObserveOperation("FindOne", "items", 20*time.Millisecond, nil)
+	m.ObserveOperation("FindOne", "items", 5*time.Millisecond, mongo.ErrNoDocuments)
+
+	if n := testutil.ToFloat64(m.operations.WithLabelValues("FindOne", "items", "ok")); n != 2 {
+		t.Errorf("ok operations = %v, want 2", n)
+	}
+	if n := testutil.ToFloat64(m.operations.WithLabelValues("FindOne", "items", "not_found")); n != 1 {
+		t.Errorf("not_found operations = %v, want 1", n)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != "mongodb_operation_duration_seconds" {
+			continue
+		}
+		h := f.GetMetric()[0].GetHistogram()
+		if h.GetSampleCount() != 3 {
+			t.Errorf("duration samples = %v, want 3", h.GetSampleCount())
+		}
+		if sum := h.GetSampleSum(); sum < 0.0349 || sum > 0.0351 {
+			t.Errorf("duration sum = %v, want 0.035", sum)
+		}
+		return
+	}
+	t.Error("mongodb_operation_duration_seconds not registered")
+}
+
+func TestNewRegistersOnce(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	New(reg)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("second New() on the same registry didn't panic")
+		}
+	}()
+	New(reg)
+}
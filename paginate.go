@@ -0,0 +1,113 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+)
+
+var (
+	ErrInvalidCursor   = errors.New("invalid page cursor")
+	ErrInvalidPageSize = errors.New("page size must be positive")
+)
+
+type pageCursor struct {
+	Value bson.RawValue `bson:"v"`
+	ID    bson.RawValue `bson:"id"`
+}
+
+// FindAfter returns the page of up to size documents matching filter that
+// follows cursor, ordered by sortField ascending and then _id, along with
+// the cursor of the next page. Unlike skip based pages, deep pages cost the
+// same as the first one. Pass an empty cursor for the first page; an empty
+// next cursor means there are no more pages.
+func (c *Collection[T]) FindAfter(ctx context.Context, filter any, cursor string, size uint32, sortField string) ([]T, string, error) {
+	// a zero limit means no limit, which would return everything as a page
+	if size == 0 {
+		return nil, "", ErrInvalidPageSize
+	}
+
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if sortField == "" {
+		sortField = "_id"
+	}
+	sort := bson.D{{Key: sortField, Value: 1}}
+	if sortField != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: 1})
+	}
+
+	if cursor != "" {
+		after, err := decodePageCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+
+		page := bson.M{"_id": bson.M{"$gt": after.ID}}
+		if sortField != "_id" {
+			// $gt doesn't cross types, but any value sorts after null
+			greater := bson.M{"$gt": after.Value}
+			if after.Value.Type == bson.TypeNull {
+				greater = bson.M{"$ne": nil}
+			}
+			page = bson.M{"$or": bson.A{
+				bson.M{sortField: greater},
+				bson.M{sortField: after.Value, "_id": bson.M{"$gt": after.ID}},
+			}}
+		}
+		filter = bson.M{"$and": bson.A{filter, page}}
+	}
+
+	docs, err := c.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(int64(size)))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(docs) == 0 || len(docs) < int(size) {
+		return docs, "", nil
+	}
+
+	next, err := encodePageCursor(docs[len(docs)-1], sortField)
+	if err != nil {
+		return nil, "", err
+	}
+	return docs, next, nil
+}
+
+func encodePageCursor(doc any, sortField string) (string, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf(ErrMsgMarshal, err)
+	}
+
+	value, err := bson.Raw(data).LookupErr(strings.Split(sortField, ".")...)
+	if err != nil {
+		// documents lacking the field sort first, as null
+		value = bson.RawValue{Type: bson.TypeNull}
+	}
+
+	cursor, err := bson.Marshal(pageCursor{Value: value, ID: bson.Raw(data).Lookup("_id")})
+	if err != nil {
+		return "", fmt.Errorf(ErrMsgMarshal, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+func decodePageCursor(cursor string) (pageCursor, error) {
+	var after pageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return after, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err = bson.Unmarshal(data, &after); err != nil || after.ID.Type == 0 {
+		return after, ErrInvalidCursor
+	}
+	return after, nil
+}
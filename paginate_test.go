@@ -0,0 +1,116 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	doc := bson.M{"_id": id, "meta": bson.M{"rank": int32(7)}}
+
+	cursor, err := encodePageCursor(doc, "meta.rank")
+	if err != nil {
+		t.Fatalf("encodePageCursor() error = %v", err)
+	}
+	after, err := decodePageCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodePageCursor() error = %v", err)
+	}
+	if got := after.ID.ObjectID(); got != id {
+		t.Errorf("cursor id = %v, want %v", got, id)
+	}
+	if got := after.Value.Int32(); got != 7 {
+		t.Errorf("cursor value = %v, want 7", got)
+	}
+}
+
+func TestPageCursorMissingField(t *testing.T) {
+	cursor, err := encodePageCursor(bson.M{"_id": "a"}, "rank")
+	if err != nil {
+		t.Fatalf("encodePageCursor() error = %v", err)
+	}
+	after, err := decodePageCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodePageCursor() error = %v", err)
+	}
+	if after.Value.Type != bson.TypeNull {
+		t.Errorf("cursor value type = %v, want null", after.Value.Type)
+	}
+}
+
+func TestDecodePageCursorMalformed(t *testing.T) {
+	noID, _ := bson.Marshal(bson.M{"v": 1})
+	for _, cursor := range []string{
+		"%%%",
+		base64.RawURLEncoding.EncodeToString([]byte("not bson")),
+		base64.RawURLEncoding.EncodeToString(noID),
+	} {
+		if _, err := decodePageCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("decodePageCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestFindAfterZeroSize(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+	if _, _, err := c.FindAfter(context.Background(), nil, "", 0, ""); !errors.Is(err, ErrInvalidPageSize) {
+		t.Errorf("FindAfter() error = %v, want ErrInvalidPageSize", err)
+	}
+}
+
+func TestFindAfterWalk(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	// ranks repeat, and some documents lack one, to exercise the _id
+	// tie-break; a multiple of the page size ends with an empty page
+	const total, size = 24, 4
+	for i := 0; i < total; i++ {
+		doc := bson.M{"_id": fmt.Sprintf("doc%02d", i)}
+		if i%5 != 0 {
+			doc["rank"] = i % 3
+		}
+		if _, err := c.InsertOne(ctx, doc); err != nil {
+			t.Fatalf("InsertOne() error = %v", err)
+		}
+	}
+
+	seen := map[any]bool{}
+	cursor, pages := "", 0
+	for {
+		docs, next, err := c.FindAfter(ctx, nil, cursor, size, "rank")
+		if err != nil {
+			t.Fatalf("FindAfter() error = %v", err)
+		}
+		pages++
+		for _, doc := range docs {
+			if seen[doc["_id"]] {
+				t.Fatalf("FindAfter() returned %v twice", doc["_id"])
+			}
+			seen[doc["_id"]] = true
+		}
+		if next == "" {
+			if len(docs) != 0 {
+				t.Errorf("last page has %d documents, want 0", len(docs))
+			}
+			break
+		}
+		if len(docs) != size {
+			t.Fatalf("page %d has %d documents, want %d", pages, len(docs), size)
+		}
+		cursor = next
+	}
+
+	if len(seen) != total {
+		t.Errorf("FindAfter() walked %d documents, want %d", len(seen), total)
+	}
+	if pages != total/size+1 {
+		t.Errorf("FindAfter() walked %d pages, want %d", pages, total/size+1)
+	}
+}
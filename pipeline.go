@@ -0,0 +1,70 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Pipeline struct {
+	stages mongo.Pipeline
+}
+
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+func (p *Pipeline) Match(filter any) *Pipeline {
+	if f, ok := filter.(Filter); ok {
+		filter = f.Build()
+	}
+	return p.Stage("$match", filter)
+}
+
+func (p *Pipeline) Group(group bson.M) *Pipeline {
+	return p.Stage("$group", group)
+}
+
+func (p *Pipeline) Sort(sort bson.D) *Pipeline {
+	return p.Stage("$sort", sort)
+}
+
+func (p *Pipeline) Limit(n int) *Pipeline {
+	return p.Stage("$limit", n)
+}
+
+func (p *Pipeline) Skip(n int) *Pipeline {
+	return p.Stage("$skip", n)
+}
+
+func (p *Pipeline) Project(projection bson.M) *Pipeline {
+	return p.Stage("$project", projection)
+}
+
+func (p *Pipeline) AddFields(fields bson.M) *Pipeline {
+	return p.Stage("$addFields", fields)
+}
+
+func (p *Pipeline) Lookup(from, localField, foreignField, as string) *Pipeline {
+	return p.Stage("$lookup", bson.M{
+		"from":         from,
+		"localField":   localField,
+		"foreignField": foreignField,
+		"as":           as,
+	})
+}
+
+func (p *Pipeline) Unwind(path string, preserveNullAndEmpty bool) *Pipeline {
+	return p.Stage("$unwind", bson.M{
+		"path":                       path,
+		"preserveNullAndEmptyArrays": preserveNullAndEmpty,
+	})
+}
+
+func (p *Pipeline) Stage(name string, value any) *Pipeline {
+	p.stages = append(p.stages, bson.D{{Key: name, Value: value}})
+	return p
+}
+
+func (p *Pipeline) Build() mongo.Pipeline {
+	return p.stages
+}
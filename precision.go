@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// TruncatedTimes returns the paths of time.Time values in doc that carry
+// sub-millisecond precision, which BSON dates silently drop on write.
+func TruncatedTimes(doc any) []string {
+	var paths []string
+	walkTimes(reflect.ValueOf(doc), "", &paths)
+	return paths
+}
+
+func walkTimes(v reflect.Value, path string, paths *[]string) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkTimes(v.Elem(), path, paths)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if t := v.Interface().(time.Time); t.Nanosecond()%int(time.Millisecond) != 0 {
+				*paths = append(*paths, path)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() {
+				walkTimes(v.Field(i), joinPath(path, bsonFieldName(f)), paths)
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walkTimes(iter.Value(), joinPath(path, fmt.Sprint(iter.Key())), paths)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkTimes(v.Index(i), joinPath(path, fmt.Sprint(i)), paths)
+		}
+	}
+}
+
+func bsonFieldName(f reflect.StructField) string {
+	if f.Anonymous && f.Tag.Get("bson") == "" {
+		return ""
+	}
+	name, opts, _ := strings.Cut(f.Tag.Get("bson"), ",")
+	// inlined fields are written at the level of their parent
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "inline" {
+			return ""
+		}
+	}
+	if name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(f.Name)
+}
+
+func joinPath(path, name string) string {
+	if path == "" || name == "" {
+		return path + name
+	}
+	return path + "." + name
+}
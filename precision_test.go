@@ -0,0 +1,37 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTruncatedTimes(t *testing.T) {
+	type (
+		Audit struct {
+			UpdatedAt time.Time `bson:"updatedAt"`
+		}
+
+		doc struct {
+			CreatedAt time.Time            `bson:"createdAt"`
+			Meta      Audit                `bson:",inline"`
+			Nested    Audit                `bson:"nested"`
+			Times     map[string]time.Time `bson:"times"`
+			Audit
+		}
+	)
+
+	precise := time.Date(2022, 7, 1, 12, 0, 0, 1500, time.UTC)
+	got := TruncatedTimes(doc{
+		CreatedAt: precise.Truncate(time.Millisecond),
+		Meta:      Audit{UpdatedAt: precise},
+		Nested:    Audit{UpdatedAt: precise},
+		Times:     map[string]time.Time{"seen": precise},
+		Audit:     Audit{UpdatedAt: precise},
+	})
+
+	want := []string{"updatedAt", "nested.updatedAt", "times.seen", "updatedAt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TruncatedTimes() = %v, want %v", got, want)
+	}
+}
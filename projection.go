@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrMixedProjection = errors.New("projection can't both include and exclude fields")
+
+// Project returns a projection keeping only fields, plus _id unless it is
+// excluded with Exclude("_id").
+func Project(fields ...string) bson.D {
+	return projectionSpec(fields, 1)
+}
+
+// Exclude returns a projection dropping fields.
+func Exclude(fields ...string) bson.D {
+	return projectionSpec(fields, 0)
+}
+
+// Projection combines projections, e.g. Project("name") with
+// Exclude("_id"). MongoDB can't mix included and excluded fields other than
+// _id, so such a mix fails with ErrMixedProjection.
+func Projection(specs ...bson.D) (bson.D, error) {
+	var projection bson.D
+	for _, spec := range specs {
+		projection = append(projection, spec...)
+	}
+	if err := checkProjection(projection); err != nil {
+		return nil, err
+	}
+	return projection, nil
+}
+
+// FindProjected runs Find with projection and decodes the documents into R,
+// typically a struct holding only the projected fields.
+func FindProjected[T, R any](ctx context.Context, c *Collection[T], filter any, projection bson.D, opts ...*options.FindOptions) ([]R, error) {
+	if err := checkProjection(projection); err != nil {
+		return nil, err
+	}
+
+	opts = append(opts[:len(opts):len(opts)], options.Find().SetProjection(projection))
+	return find[T, R](ctx, c, "FindProjected", filter, opts...)
+}
+
+func projectionSpec(fields []string, value int) bson.D {
+	spec := make(bson.D, len(fields))
+	for i, field := range fields {
+		spec[i] = bson.E{Key: field, Value: value}
+	}
+	return spec
+}
+
+func checkProjection(projection bson.D) error {
+	var included, excluded string
+	for _, e := range projection {
+		if e.Key == "_id" {
+			continue
+		}
+		include, ok := projectionFlag(e.Value)
+		switch {
+		case ok && include:
+			included = e.Key
+		case ok:
+			excluded = e.Key
+		}
+	}
+	if included != "" && excluded != "" {
+		return fmt.Errorf("%w: %s and %s", ErrMixedProjection, included, excluded)
+	}
+	return nil
+}
+
+// projectionFlag reports whether v includes or excludes a field; ok is
+// false for other values such as $slice or expressions.
+func projectionFlag(v any) (include bool, ok bool) {
+	switch v := v.(type) {
+	case bool:
+		return v, true
+	case int:
+		return v != 0, true
+	case int32:
+		return v != 0, true
+	case int64:
+		return v != 0, true
+	case float64:
+		return v != 0, true
+	}
+	return false, false
+}
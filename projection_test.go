@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+)
+
+func TestProjectionMixed(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []bson.D
+	}{
+		{"helpers", []bson.D{Project("name"), Exclude("age")}},
+		{"int32", []bson.D{{{Key: "name", Value: int32(1)}, {Key: "age", Value: int32(0)}}}},
+		{"int64", []bson.D{{{Key: "name", Value: int64(1)}, {Key: "age", Value: false}}}},
+		{"float64", []bson.D{{{Key: "name", Value: true}, {Key: "age", Value: 0.0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Projection(tt.specs...); !errors.Is(err, ErrMixedProjection) {
+				t.Errorf("Projection() error = %v, want ErrMixedProjection", err)
+			}
+		})
+	}
+}
+
+func TestProjectionExcludeID(t *testing.T) {
+	projection, err := Projection(Project("name", "age"), Exclude("_id"))
+	if err != nil {
+		t.Fatalf("Projection() error = %v", err)
+	}
+	want := bson.D{{Key: "name", Value: 1}, {Key: "age", Value: 1}, {Key: "_id", Value: 0}}
+	if len(projection) != len(want) {
+		t.Fatalf("Projection() = %v, want %v", projection, want)
+	}
+	for i := range want {
+		if projection[i] != want[i] {
+			t.Errorf("Projection()[%d] = %v, want %v", i, projection[i], want[i])
+		}
+	}
+}
+
+func TestFindProjected(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	if _, err := c.InsertOne(ctx, bson.M{"name": "alice", "age": 30, "email": "alice@example.com"}); err != nil {
+		t.Fatalf("InsertOne() error = %v", err)
+	}
+
+	type summary struct {
+		Name  string `bson:"name"`
+		Age   int    `bson:"age"`
+		Email string `bson:"email"`
+	}
+	docs, err := FindProjected[bson.M, summary](ctx, c, nil, Project("name", "age"))
+	if err != nil {
+		t.Fatalf("FindProjected() error = %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("FindProjected() returned %d documents, want 1", len(docs))
+	}
+	if want := (summary{Name: "alice", Age: 30}); docs[0] != want {
+		t.Errorf("FindProjected() = %+v, want %+v", docs[0], want)
+	}
+}
@@ -0,0 +1,25 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ClaimNext atomically picks the next document matching ready and sets the
+// claim fields on it. It returns false when no job is available; pass a sort
+// through opts to control which job is picked first.
+func (c *Collection[T]) ClaimNext(ctx context.Context, ready bson.M, claim bson.M, opts ...*options.FindOneAndUpdateOptions) (T, bool, error) {
+	opts = append(opts[:len(opts):len(opts)], options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	doc, err := c.FindOneAndUpdate(ctx, ready, bson.M{"$set": claim}, opts...)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return doc, false, nil
+	}
+	if err != nil {
+		return doc, false, err
+	}
+	return doc, true, nil
+}
@@ -0,0 +1,54 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const referencesChunkSize = 1000
+
+// CheckReferences returns the ids referenced by refField in from that don't
+// exist as _id in to, i.e. the dangling references.
+func CheckReferences[T, R any](ctx context.Context, from *Collection[T], refField string, to *Collection[R]) ([]string, error) {
+	refs, err := from.Distinct(ctx, refField, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var dangling []string
+	for len(refs) > 0 {
+		n := referencesChunkSize
+		if n > len(refs) {
+			n = len(refs)
+		}
+		chunk := refs[:n]
+		refs = refs[n:]
+
+		// decoded like refs, so both sides compare as the same BSON values
+		ids, err := to.Distinct(ctx, "_id", bson.M{"_id": bson.M{"$in": chunk}})
+		if err != nil {
+			return nil, err
+		}
+
+		existing := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			existing[valueKey(id)] = true
+		}
+		for _, ref := range chunk {
+			if ref != nil && !existing[valueKey(ref)] {
+				dangling = append(dangling, idString(ref))
+			}
+		}
+	}
+	return dangling, nil
+}
+
+// valueKey identifies v by its BSON type and encoding, so e.g. the int32 5
+// and the string "5" don't collide.
+func valueKey(v any) string {
+	t, data, err := bson.MarshalValue(v)
+	if err != nil {
+		return idString(v)
+	}
+	return string(append([]byte{byte(t)}, data...))
+}
@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+func TestValueKey(t *testing.T) {
+	oid := primitive.NewObjectID()
+	if valueKey(oid) != valueKey(primitive.ObjectID(oid)) {
+		t.Error("equal ObjectIDs have different keys")
+	}
+	if valueKey(int32(5)) != valueKey(int32(5)) {
+		t.Error("equal int32 values have different keys")
+	}
+	if valueKey(int32(5)) == valueKey("5") {
+		t.Error("int32 5 and string \"5\" have the same key")
+	}
+	if valueKey(oid) == valueKey(oid.Hex()) {
+		t.Error("ObjectID and its hex string have the same key")
+	}
+}
+
+func TestCheckReferences(t *testing.T) {
+	ctx := context.Background()
+	users := testCollection[bson.M](t)
+	cards := NewCollection[bson.M](users.Inner.Database(), "cards", zerolog.Nop())
+
+	oid := primitive.NewObjectID()
+	insertAll(t, users, bson.M{"_id": int32(5)}, bson.M{"_id": oid}, bson.M{"_id": "7"})
+	insertAll(t, cards,
+		bson.M{"owner": int32(5)},
+		bson.M{"owner": oid},
+		bson.M{"owner": int32(7)},
+		bson.M{"owner": primitive.NewObjectID()},
+		bson.M{"owner": nil},
+	)
+
+	dangling, err := CheckReferences(ctx, cards, "owner", users)
+	if err != nil {
+		t.Fatalf("CheckReferences() error = %v", err)
+	}
+	// the int32 7 must not match the string "7", though both print as 7
+	if len(dangling) != 2 {
+		t.Errorf("CheckReferences() = %v, want the int32 7 and the unknown ObjectID", dangling)
+	}
+	for _, id := range dangling {
+		if id == "5" || id == oid.Hex() {
+			t.Errorf("CheckReferences() reported existing id %s", id)
+		}
+	}
+}
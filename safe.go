@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrFieldNotAllowed = errors.New("filter field not allowed")
+
+// SafeFind runs Find with a user supplied filter after checking that it only
+// queries allowedFields. Fields nested in $and, $or and $nor are checked as
+// well; any other top-level operator, such as $where or $expr, is rejected.
+func (c *Collection[T]) SafeFind(ctx context.Context, filter bson.M, allowedFields map[string]bool, opts ...*options.FindOptions) ([]T, error) {
+	if err := checkFilterFields(filter, allowedFields); err != nil {
+		return nil, err
+	}
+	return c.Find(ctx, filter, opts...)
+}
+
+func checkFilterFields(filter bson.M, allowedFields map[string]bool) error {
+	for field, value := range filter {
+		switch field {
+		case "$and", "$or", "$nor":
+			clauses, ok := filterClauses(value)
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrFieldNotAllowed, field)
+			}
+			for _, clause := range clauses {
+				if err := checkFilterFields(clause, allowedFields); err != nil {
+					return err
+				}
+			}
+		default:
+			if !allowedFields[field] {
+				return fmt.Errorf("%w: %s", ErrFieldNotAllowed, field)
+			}
+		}
+	}
+	return nil
+}
+
+func filterClauses(value any) ([]bson.M, bool) {
+	switch v := value.(type) {
+	case []bson.M:
+		return v, true
+	case bson.A:
+		return filterClauses([]any(v))
+	case []any:
+		clauses := make([]bson.M, len(v))
+		for i, clause := range v {
+			m, ok := clause.(bson.M)
+			if !ok {
+				return nil, false
+			}
+			clauses[i] = m
+		}
+		return clauses, true
+	}
+	return nil, false
+}
@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	cursorNotFoundCode = 43
+	// maxStalledResumes bounds the resumes in a row that make no progress,
+	// e.g. when the cursor is killed before its first batch every time.
+	maxStalledResumes = 3
+)
+
+// Scan calls fn for every document matching filter in _id order. Scans can
+// outlive the server-side cursor, so on CursorNotFound the query is re-issued
+// from the last _id seen instead of failing the whole scan. Any sort in opts
+// is overridden. The scan gives up with the CursorNotFound error after
+// maxStalledResumes resumes in a row without a new document.
+func (c *Collection[T]) Scan(ctx context.Context, filter any, fn func(T) error, opts ...*options.FindOptions) (err error) {
+	ctx, o := c.begin(ctx, "Scan")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return err
+	}
+
+	opts = c.findOptions(ctx, append(opts[:len(opts):len(opts)], options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})))
+
+	var (
+		last, next *bson.RawValue
+		stalled    int
+	)
+	for {
+		query := filter
+		if last != nil {
+			query = bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$gt": *last}}}}
+		}
+
+		if next, err = c.scan(ctx, query, fn, last, opts); !isCursorNotFound(err) {
+			return err
+		}
+
+		if next == last {
+			stalled++
+		} else {
+			stalled = 0
+		}
+		if last = next; stalled >= maxStalledResumes {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		c.Log.Warn().Err(err).Str("collection", c.Inner.Name()).Msg("cursor lost, resuming scan")
+	}
+}
+
+func (c *Collection[T]) scan(ctx context.Context, filter any, fn func(T) error, last *bson.RawValue, opts []*options.FindOptions) (*bson.RawValue, error) {
+	cur, err := c.Inner.Find(c.withSession(ctx), filter, opts...)
+	if err != nil {
+		return last, fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(ctx) {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return last, fmt.Errorf(ErrMsgDecode, err)
+		}
+		if err = fn(doc); err != nil {
+			return last, err
+		}
+
+		id := cur.Current.Lookup("_id")
+		last = &bson.RawValue{Type: id.Type, Value: append([]byte(nil), id.Value...)}
+	}
+
+	if err = cur.Err(); err != nil {
+		return last, fmt.Errorf(ErrMsgQuery, err)
+	}
+	return last, nil
+}
+
+func isCursorNotFound(err error) bool {
+	var se mongo.ServerError
+	return errors.As(err, &se) && se.HasErrorCode(cursorNotFoundCode)
+}
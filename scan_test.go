@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+)
+
+func TestIsCursorNotFound(t *testing.T) {
+	lost := mongo.CommandError{Code: cursorNotFoundCode, Name: "CursorNotFound"}
+	if !isCursorNotFound(fmt.Errorf(ErrMsgQuery, lost)) {
+		t.Error("isCursorNotFound() = false for a wrapped CursorNotFound error")
+	}
+	if isCursorNotFound(mongo.CommandError{Code: 11000}) || isCursorNotFound(errors.New("cursor not found")) {
+		t.Error("isCursorNotFound() = true for another error")
+	}
+}
+
+func TestScan(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	for i := 5; i > 0; i-- {
+		if _, err := c.InsertOne(ctx, bson.M{"_id": i}); err != nil {
+			t.Fatalf("InsertOne() error = %v", err)
+		}
+	}
+
+	var ids []any
+	err := c.Scan(ctx, bson.M{"_id": bson.M{"$gt": 1}}, func(doc bson.M) error {
+		ids = append(ids, doc["_id"])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if fmt.Sprint(ids) != "[2 3 4 5]" {
+		t.Errorf("Scan() visited %v, want [2 3 4 5]", ids)
+	}
+
+	stop := errors.New("stop")
+	if err = c.Scan(ctx, nil, func(bson.M) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("Scan() error = %v, want the callback's error", err)
+	}
+}
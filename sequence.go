@@ -0,0 +1,40 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NextSequence atomically increments the counter document with the given
+// name and returns its new value, starting from 1.
+func (c *Collection[T]) NextSequence(ctx context.Context, name string) (_ int64, err error) {
+	ctx, o := c.begin(ctx, "NextSequence")
+	defer func() { o.end(err) }()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if comment, ok := c.comment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	r := c.Inner.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": int64(1)}},
+		opts,
+	)
+
+	counter, err := DecodeOne[struct {
+		Seq int64 `bson:"seq"`
+	}](r)
+	if err != nil {
+		return 0, err
+	}
+
+	c.debug(ctx).Str("sequence", name).Int64("value", counter.Seq).Msg("sequence incremented")
+
+	return counter.Seq, nil
+}
@@ -0,0 +1,146 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"time"
+)
+
+const (
+	writeConflictCode = 112
+	retryBackoff      = 10 * time.Millisecond
+	commitAttempts    = 3
+)
+
+func (c *Collection[T]) WithCausalConsistency(ctx context.Context, fn func(mongo.SessionContext) error) error {
+	sess, err := c.Inner.Database().Client().StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.Background())
+
+	return mongo.WithSession(ctx, sess, fn)
+}
+
+// WithTransaction runs fn in a transaction, letting the driver retry it on
+// TransientTransactionError and the commit on UnknownTransactionCommitResult.
+// An error returned by fn aborts the transaction and is returned as is.
+func (c *Collection[T]) WithTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error, opts ...*options.TransactionOptions) (err error) {
+	ctx, o := c.begin(ctx, "WithTransaction")
+	defer func() { o.end(err) }()
+
+	sess, err := c.Inner.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.Background())
+
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	}, opts...)
+	return err
+}
+
+// WithRetryableTransaction runs fn in a transaction and starts over, with
+// exponential backoff, when it fails on a write conflict or another
+// TransientTransactionError, at most maxRetries times. Unlike WithTransaction
+// the driver doesn't retry on its own. fn must re-read whatever state it
+// depends on, since every attempt is a new transaction.
+func (c *Collection[T]) WithRetryableTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error, maxRetries int) (err error) {
+	ctx, o := c.begin(ctx, "WithRetryableTransaction")
+	defer func() { o.end(err) }()
+
+	sess, err := c.Inner.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.Background())
+
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		if err = runTransaction(ctx, sess, fn); err == nil || attempt > maxRetries || !isTransient(err) {
+			return err
+		}
+
+		c.Log.Warn().Err(err).Str("collection", c.Inner.Name()).Int("attempt", attempt).Msg("transaction write conflict")
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// runTransaction runs fn in a single transaction, retrying only a commit
+// whose outcome is unknown, since running fn again could apply it twice.
+func runTransaction(ctx context.Context, sess mongo.Session, fn func(sc mongo.SessionContext) error) error {
+	if err := sess.StartTransaction(); err != nil {
+		return err
+	}
+
+	if err := mongo.WithSession(ctx, sess, fn); err != nil {
+		_ = sess.AbortTransaction(context.Background())
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := sess.CommitTransaction(ctx)
+		if err == nil || attempt >= commitAttempts || !hasErrorLabel(err, driver.UnknownTransactionCommitResult) || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+func isTransient(err error) bool {
+	return isWriteConflict(err) || hasErrorLabel(err, driver.TransientTransactionError)
+}
+
+func isWriteConflict(err error) bool {
+	var se mongo.ServerError
+	return errors.As(err, &se) && se.HasErrorCode(writeConflictCode)
+}
+
+func hasErrorLabel(err error, label string) bool {
+	var se mongo.ServerError
+	return errors.As(err, &se) && se.HasErrorLabel(label)
+}
+
+// UnitOfWork is a causally consistent session shared by the collections
+// bound to it, so reads through any of them see the writes made through the
+// others. Like the session, it must not be used concurrently.
+type UnitOfWork struct {
+	Session mongo.Session
+}
+
+func NewUnitOfWork(client *mongo.Client) (*UnitOfWork, error) {
+	sess, err := client.StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return nil, err
+	}
+	return &UnitOfWork{Session: sess}, nil
+}
+
+func (u *UnitOfWork) End(ctx context.Context) {
+	u.Session.EndSession(ctx)
+}
+
+// Bind returns a copy of c running all its operations in uow's session.
+func (c *Collection[T]) Bind(uow *UnitOfWork) *Collection[T] {
+	cc := *c
+	cc.session = uow.Session
+	return &cc
+}
+
+// withSession attaches the bound session, if any, to ctx unless ctx
+// already carries one.
+func (c *Collection[T]) withSession(ctx context.Context) context.Context {
+	if c.session == nil || mongo.SessionFromContext(ctx) != nil {
+		return ctx
+	}
+	return mongo.NewSessionContext(ctx, c.session)
+}
@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"testing"
+)
+
+// transactions that run no operation never reach the server, so the retry
+// logic is tested offline
+var transientConflict = mongo.CommandError{
+	Code:   writeConflictCode,
+	Name:   "WriteConflict",
+	Labels: []string{driver.TransientTransactionError},
+}
+
+func TestWithRetryableTransaction(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+
+	var attempts int
+	err := c.WithRetryableTransaction(context.Background(), func(mongo.SessionContext) error {
+		if attempts++; attempts < 3 {
+			return transientConflict
+		}
+		return nil
+	}, 5)
+	if err != nil {
+		t.Fatalf("WithRetryableTransaction() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("WithRetryableTransaction() made %d attempts, want 3", attempts)
+	}
+}
+
+func TestWithRetryableTransactionMaxRetries(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+
+	var attempts int
+	err := c.WithRetryableTransaction(context.Background(), func(mongo.SessionContext) error {
+		attempts++
+		return transientConflict
+	}, 1)
+	if !isWriteConflict(err) || attempts != 2 {
+		t.Errorf("WithRetryableTransaction() = %v after %d attempts, want the conflict after 2", err, attempts)
+	}
+}
+
+func TestWithRetryableTransactionPermanentError(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+
+	other := errors.New("boom")
+	var attempts int
+	err := c.WithRetryableTransaction(context.Background(), func(mongo.SessionContext) error {
+		attempts++
+		return other
+	}, 5)
+	if !errors.Is(err, other) || attempts != 1 {
+		t.Errorf("WithRetryableTransaction() = %v after %d attempts, want boom without retrying", err, attempts)
+	}
+}
+
+func TestWithRetryableTransactionCancel(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts int
+	err := c.WithRetryableTransaction(ctx, func(mongo.SessionContext) error {
+		attempts++
+		cancel()
+		return transientConflict
+	}, 5)
+	if !errors.Is(err, context.Canceled) || attempts != 1 {
+		t.Errorf("WithRetryableTransaction() = %v after %d attempts, want context.Canceled after 1", err, attempts)
+	}
+}
+
+// illegalOperationCode is returned by standalone servers for transactions.
+const illegalOperationCode = 20
+
+func TestWithRetryableTransactionCommits(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+
+	// collections can't be created implicitly in transactions before 4.4
+	insertAll(t, c, bson.M{"n": 0})
+
+	var attempts int
+	err := c.WithRetryableTransaction(ctx, func(sc mongo.SessionContext) error {
+		attempts++
+		if _, err := c.InsertOne(sc, bson.M{"n": attempts}); err != nil {
+			return err
+		}
+		if attempts < 2 {
+			// the insert of the failed attempt is rolled back
+			return transientConflict
+		}
+		return nil
+	}, 5)
+	var se mongo.ServerError
+	if errors.As(err, &se) && se.HasErrorCode(illegalOperationCode) {
+		t.Skip("transactions need a replica set")
+	}
+	if err != nil {
+		t.Fatalf("WithRetryableTransaction() error = %v", err)
+	}
+
+	if n, err := c.Count(ctx, nil); err != nil || n != 2 {
+		t.Errorf("Count() = %d, %v, want the first document and the committed attempt", n, err)
+	}
+}
+
+func TestWithTransaction(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+
+	// the driver retries only errors returned as is, not wrapped
+	var attempts int
+	err := c.WithTransaction(context.Background(), func(mongo.SessionContext) error {
+		if attempts++; attempts == 1 {
+			return transientConflict
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("WithTransaction() made %d attempts, want 2", attempts)
+	}
+}
+
+func TestWithTransactionError(t *testing.T) {
+	c := offlineCollection[bson.M](t)
+
+	stop := errors.New("stop")
+	var attempts int
+	err := c.WithTransaction(context.Background(), func(mongo.SessionContext) error {
+		attempts++
+		return fmt.Errorf("transfer: %w", stop)
+	})
+	if !errors.Is(err, stop) || attempts != 1 {
+		t.Errorf("WithTransaction() = %v after %d attempts, want fn's error after 1", err, attempts)
+	}
+}
@@ -0,0 +1,190 @@
+package mongodb
+
+import (
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FilterLogging controls whether debug logs include operation filters.
+type FilterLogging int
+
+const (
+	FilterLogOff FilterLogging = iota
+	// FilterLogRedacted logs the shape of filters, values replaced by "?".
+	FilterLogRedacted
+	FilterLogFull
+)
+
+type (
+	// SlowOp is an operation that exceeded the slow threshold. Filter only
+	// keeps the shape of the filter, values are replaced by "?".
+	SlowOp struct {
+		Operation  string
+		Collection string
+		Filter     string
+		Took       time.Duration
+		At         time.Time
+	}
+
+	slowRing struct {
+		mu   sync.Mutex
+		ops  []SlowOp
+		next int
+		full bool
+	}
+)
+
+// WithSlowSamples keeps the last n operations exceeding the slow threshold
+// in memory, for SlowSamples to serve e.g. on a debug endpoint.
+func WithSlowSamples(n int) CollectionOption {
+	return func(o *collectionOptions) {
+		o.SlowSamples = n
+	}
+}
+
+// WithFilterLogging adds the filter of each operation to its debug log, as
+// is or redacted to its shape when it may hold personal data.
+func WithFilterLogging(mode FilterLogging) CollectionOption {
+	return func(o *collectionOptions) {
+		o.LogFilters = mode
+	}
+}
+
+// SlowSamples returns the recorded slow operations, oldest first.
+func (c *Collection[T]) SlowSamples() []SlowOp {
+	if c.slow == nil {
+		return nil
+	}
+	return c.slow.list()
+}
+
+// slowQuery reports an operation that exceeded the slow threshold at warn
+// level, with its query plan when explaining is enabled, and records it;
+// faster ones are ignored.
+func (c *Collection[T]) slowQuery(op string, filter any, took time.Duration) {
+	if !c.isSlow(took) {
+		return
+	}
+
+	c.recordSlow(op, filter, took)
+
+	if c.ExplainSlow {
+		c.explainSlow(op, filter, took)
+		return
+	}
+
+	c.Log.Warn().Str("collection", c.Inner.Name()).Str("operation", op).Str("filter", filterShape(filter)).Dur("took", took).Msg("slow query")
+}
+
+// slowAggregate is slowQuery for aggregations, which can't be explained as
+// a find.
+func (c *Collection[T]) slowAggregate(pipeline any, took time.Duration) {
+	if !c.isSlow(took) {
+		return
+	}
+
+	stages := bson.D{{Key: "pipeline", Value: pipeline}}
+	c.recordSlow("aggregate", stages, took)
+
+	c.Log.Warn().Str("collection", c.Inner.Name()).Str("operation", "aggregate").Str("pipeline", filterShape(stages)).Dur("took", took).Msg("slow query")
+}
+
+func (c *Collection[T]) isSlow(took time.Duration) bool {
+	return c.SlowThreshold > 0 && took >= c.SlowThreshold
+}
+
+func (c *Collection[T]) recordSlow(op string, filter any, took time.Duration) {
+	if c.slow == nil {
+		return
+	}
+
+	c.slow.add(SlowOp{
+		Operation:  op,
+		Collection: c.Inner.Name(),
+		Filter:     filterShape(filter),
+		Took:       took,
+		At:         time.Now(),
+	})
+}
+
+func (c *Collection[T]) logFilter(filter any) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		switch c.LogFilters {
+		case FilterLogRedacted:
+			e.Str("filter", filterShape(filter))
+		case FilterLogFull:
+			if data, err := bson.MarshalExtJSON(filter, false, false); err == nil {
+				e.RawJSON("filter", data)
+			}
+		}
+	}
+}
+
+func newSlowRing(n int) *slowRing {
+	return &slowRing{ops: make([]SlowOp, n)}
+}
+
+func (r *slowRing) add(op SlowOp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ops[r.next] = op
+	if r.next = (r.next + 1) % len(r.ops); r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *slowRing) list() []SlowOp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]SlowOp(nil), r.ops[:r.next]...)
+	}
+	return append(append([]SlowOp(nil), r.ops[r.next:]...), r.ops[:r.next]...)
+}
+
+func filterShape(filter any) string {
+	data, err := bson.Marshal(filter)
+	if err != nil {
+		return "?"
+	}
+
+	var b strings.Builder
+	writeShape(&b, bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: data})
+	return b.String()
+}
+
+func writeShape(b *strings.Builder, v bson.RawValue) {
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elems, _ := v.Document().Elements()
+		sort.Slice(elems, func(i, j int) bool { return elems[i].Key() < elems[j].Key() })
+		b.WriteString("{")
+		for i, e := range elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(e.Key())
+			b.WriteString(": ")
+			writeShape(b, e.Value())
+		}
+		b.WriteString("}")
+	case bson.TypeArray:
+		values, _ := v.Array().Values()
+		b.WriteString("[")
+		for i, e := range values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeShape(b, e)
+		}
+		b.WriteString("]")
+	default:
+		b.WriteString("?")
+	}
+}
@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+func TestFilterShape(t *testing.T) {
+	tests := []struct {
+		filter any
+		want   string
+	}{
+		{bson.M{"email": "alice@example.com", "age": bson.M{"$gt": 30}}, "{age: {$gt: ?}, email: ?}"},
+		{bson.M{"$or": bson.A{bson.M{"a": 1}, bson.M{"b": 2}}}, "{$or: [{a: ?}, {b: ?}]}"},
+		{bson.M{}, "{}"},
+		{"not a document", "?"},
+	}
+	for _, tt := range tests {
+		if got := filterShape(tt.filter); got != tt.want {
+			t.Errorf("filterShape(%v) = %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestSlowRing(t *testing.T) {
+	r := newSlowRing(3)
+	if ops := r.list(); len(ops) != 0 {
+		t.Errorf("list() = %v, want empty", ops)
+	}
+
+	for i := 1; i <= 5; i++ {
+		r.add(SlowOp{Operation: "find", Took: time.Duration(i)})
+
+		want := i
+		if want > 3 {
+			want = 3
+		}
+		ops := r.list()
+		if len(ops) != want {
+			t.Fatalf("after %d adds list() has %d ops, want %d", i, len(ops), want)
+		}
+		// oldest first, so the last one is the latest added
+		if ops[0].Took != time.Duration(i-len(ops)+1) || ops[len(ops)-1].Took != time.Duration(i) {
+			t.Errorf("after %d adds list() = %v, want the latest %d oldest first", i, ops, len(ops))
+		}
+	}
+}
+
+func TestSlowSamples(t *testing.T) {
+	c := offlineCollection[bson.M](t, WithSlowThreshold(time.Millisecond), WithSlowSamples(2))
+
+	c.slowQuery("find", bson.M{"email": "alice@example.com"}, time.Second)
+	c.slowQuery("find", bson.M{"fast": true}, time.Microsecond)
+
+	ops := c.SlowSamples()
+	if len(ops) != 1 {
+		t.Fatalf("SlowSamples() = %v, want only the slow operation", ops)
+	}
+	if ops[0].Filter != "{email: ?}" || ops[0].Collection != "items" || ops[0].Took != time.Second {
+		t.Errorf("SlowSamples()[0] = %+v, want the redacted slow find", ops[0])
+	}
+
+	if samples := offlineCollection[bson.M](t).SlowSamples(); samples != nil {
+		t.Errorf("SlowSamples() = %v without sampling, want nil", samples)
+	}
+}
+
+// logEntries parses the JSON lines zerolog wrote to buf.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("log line %s: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	var buf bytes.Buffer
+	c := offlineCollection[bson.M](t, WithSlowThreshold(time.Millisecond))
+	c.Log = zerolog.New(&buf).Level(zerolog.DebugLevel)
+
+	c.slowQuery("find", bson.M{"email": "alice@example.com"}, time.Microsecond)
+	if buf.Len() > 0 {
+		t.Errorf("fast operation logged %s, want nothing", buf.String())
+	}
+
+	c.slowQuery("find", bson.M{"email": "alice@example.com"}, time.Second)
+	entries := logEntries(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("slow operation logged %d entries, want 1", len(entries))
+	}
+	if e := entries[0]; e["level"] != "warn" || e["message"] != "slow query" || e["operation"] != "find" || e["filter"] != "{email: ?}" {
+		t.Errorf("log entry = %v, want a warn-level slow query for the redacted find", e)
+	}
+}
+
+func TestSlowQueryLevels(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	c := testCollection[bson.M](t, WithSlowThreshold(time.Hour))
+	c.Log = zerolog.New(&buf).Level(zerolog.DebugLevel)
+	insertAll(t, c, bson.M{"n": 1})
+
+	buf.Reset()
+	if _, err := c.FindOne(ctx, bson.M{"n": 1}); err != nil {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	for _, e := range logEntries(t, &buf) {
+		if e["level"] != "debug" {
+			t.Errorf("fast FindOne() logged %v, want debug level only", e)
+		}
+	}
+
+	c.SlowThreshold = time.Nanosecond
+	buf.Reset()
+	if _, err := c.FindOne(ctx, bson.M{"n": 1}); err != nil {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	var slow bool
+	for _, e := range logEntries(t, &buf) {
+		slow = slow || e["level"] == "warn" && e["message"] == "slow query"
+	}
+	if !slow {
+		t.Errorf("slow FindOne() logged %s, want a warn-level slow query", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SplitCollection routes reads to Reader and writes to Writer, for
+// deployments reading from endpoints separate from the primary. Reads may
+// not see writes made just before; use Writer directly when they must.
+type SplitCollection[T any] struct {
+	Reader *Collection[T]
+	Writer *Collection[T]
+}
+
+// NewSplitCollection opens collection name on both databases, which usually
+// come from clients connected to different URIs.
+func NewSplitCollection[T any](readDB, writeDB *mongo.Database, name string, log zerolog.Logger, opts ...CollectionOption) *SplitCollection[T] {
+	return &SplitCollection[T]{
+		Reader: NewCollection[T](readDB, name, log, opts...),
+		Writer: NewCollection[T](writeDB, name, log, opts...),
+	}
+}
+
+func (s *SplitCollection[T]) FindOne(ctx context.Context, filter any, opts ...*options.FindOneOptions) (T, error) {
+	return s.Reader.FindOne(ctx, filter, opts...)
+}
+
+func (s *SplitCollection[T]) Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]T, error) {
+	return s.Reader.Find(ctx, filter, opts...)
+}
+
+func (s *SplitCollection[T]) Count(ctx context.Context, filter any, opts ...*options.CountOptions) (uint64, error) {
+	return s.Reader.Count(ctx, filter, opts...)
+}
+
+func (s *SplitCollection[T]) Distinct(ctx context.Context, fieldName string, filter any, opts ...*options.DistinctOptions) ([]any, error) {
+	return s.Reader.Distinct(ctx, fieldName, filter, opts...)
+}
+
+func (s *SplitCollection[T]) InsertOne(ctx context.Context, doc T, opts ...*options.InsertOneOptions) (string, error) {
+	return s.Writer.InsertOne(ctx, doc, opts...)
+}
+
+func (s *SplitCollection[T]) UpdateOne(ctx context.Context, filter any, update any, opts ...*options.UpdateOptions) error {
+	return s.Writer.UpdateOne(ctx, filter, update, opts...)
+}
+
+func (s *SplitCollection[T]) UpdateMany(ctx context.Context, filter any, update any, opts ...*options.UpdateOptions) (matched, modified uint64, err error) {
+	return s.Writer.UpdateMany(ctx, filter, update, opts...)
+}
+
+func (s *SplitCollection[T]) Upsert(ctx context.Context, filter any, update any, opts ...*options.UpdateOptions) (string, error) {
+	return s.Writer.Upsert(ctx, filter, update, opts...)
+}
+
+func (s *SplitCollection[T]) ReplaceOne(ctx context.Context, filter any, replacement T, opts ...*options.ReplaceOptions) error {
+	return s.Writer.ReplaceOne(ctx, filter, replacement, opts...)
+}
+
+// FindOneAndUpdate goes to Writer since it writes.
+func (s *SplitCollection[T]) FindOneAndUpdate(ctx context.Context, filter any, update any, opts ...*options.FindOneAndUpdateOptions) (T, error) {
+	return s.Writer.FindOneAndUpdate(ctx, filter, update, opts...)
+}
+
+func (s *SplitCollection[T]) DeleteOne(ctx context.Context, filter any, opts ...*options.DeleteOptions) error {
+	return s.Writer.DeleteOne(ctx, filter, opts...)
+}
+
+func (s *SplitCollection[T]) DeleteMany(ctx context.Context, filter any, opts ...*options.DeleteOptions) (uint64, error) {
+	return s.Writer.DeleteMany(ctx, filter, opts...)
+}
@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// FindStream sends matching documents on the returned channel as they are
+// decoded instead of loading them all into memory. The document channel is
+// closed once the cursor is exhausted or fails; the error channel then
+// receives at most one error and is closed as well. A consumer that stops
+// reading early must cancel ctx, which closes the cursor. The stream is
+// bounded only by ctx.
+func (c *Collection[T]) FindStream(ctx context.Context, filter any, opts ...*options.FindOptions) (<-chan T, <-chan error) {
+	docs := make(chan T)
+	errs := make(chan error, 1)
+
+	// the operation ends with the stream, not with this call
+	ctx, o := c.begin(ctx, "FindStream")
+
+	filter, err := NormalizeFilter(filter)
+	if err != nil {
+		o.end(err)
+		close(docs)
+		errs <- err
+		close(errs)
+		return docs, errs
+	}
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.FindOptions{options.Find().SetComment(comment)}, opts...)
+	}
+
+	c.debug(ctx).Msg("documents stream")
+
+	go func() {
+		defer close(errs)
+		defer close(docs)
+
+		cur, err := c.Inner.Find(c.withSession(ctx), filter, opts...)
+		if err != nil {
+			err = fmt.Errorf(ErrMsgQuery, err)
+			o.end(err)
+			errs <- err
+			return
+		}
+		defer cur.Close(context.Background())
+
+		err = sendAll(ctx, cur, docs)
+		o.end(err)
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return docs, errs
+}
+
+// ForEach calls fn for every document matching filter, decoding one at a
+// time, and stops at the first error fn returns. The iteration is bounded
+// only by ctx.
+func (c *Collection[T]) ForEach(ctx context.Context, filter any, fn func(T) error, opts ...*options.FindOptions) (err error) {
+	ctx, o := c.begin(ctx, "ForEach")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return err
+	}
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.FindOptions{options.Find().SetComment(comment)}, opts...)
+	}
+
+	c.debug(ctx).Msg("documents iterate")
+
+	start := time.Now()
+	cur, err := c.Inner.Find(c.withSession(ctx), filter, opts...)
+	if err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	n := 0
+	for ; cur.Next(ctx); n++ {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return fmt.Errorf(ErrMsgDecode, err)
+		}
+		if err = fn(doc); err != nil {
+			return err
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Int("count", n).Dur("took", time.Since(start)).Msg("documents iterated")
+
+	return nil
+}
+
+func sendAll[T any](ctx context.Context, cur *mongo.Cursor, docs chan<- T) error {
+	for cur.Next(ctx) {
+		var doc T
+		if err := cur.Decode(&doc); err != nil {
+			return fmt.Errorf(ErrMsgDecode, err)
+		}
+
+		select {
+		case docs <- doc:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if err := cur.Err(); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	return nil
+}
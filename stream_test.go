@@ -0,0 +1,112 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindStream(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	insertAll(t, c, bson.M{"n": 1}, bson.M{"n": 2}, bson.M{"n": 3})
+
+	docs, errs := c.FindStream(ctx, nil, SortAsc("n"))
+	var n int
+	for range docs {
+		n++
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("FindStream() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("FindStream() sent %d documents, want 3", n)
+	}
+}
+
+func TestFindStreamCancel(t *testing.T) {
+	c := testCollection[bson.M](t)
+	for i := 0; i < 200; i++ {
+		insertAll(t, c, bson.M{"n": i})
+	}
+
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// a small batch keeps the cursor open on the server after the first read
+	docs, errs := c.FindStream(ctx, nil, options.Find().SetBatchSize(2))
+	<-docs
+	cancel()
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("FindStream() didn't stop after cancel")
+	}
+	if _, open := <-docs; open {
+		t.Error("FindStream() left the document channel open")
+	}
+
+	// the stream goroutine returns once the cursor is closed
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines running after cancel, want at most %d", n, before)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	insertAll(t, c, bson.M{"n": 1}, bson.M{"n": 2}, bson.M{"n": 3})
+
+	var sum int32
+	err := c.ForEach(ctx, nil, func(doc bson.M) error {
+		sum += doc["n"].(int32)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach() error = %v", err)
+	}
+	if sum != 6 {
+		t.Errorf("ForEach() visited a sum of %d, want 6", sum)
+	}
+
+	stop := errors.New("stop")
+	var visited int
+	err = c.ForEach(ctx, nil, func(bson.M) error {
+		visited++
+		return stop
+	})
+	if !errors.Is(err, stop) || visited != 1 {
+		t.Errorf("ForEach() = %v after %d documents, want the callback's error after 1", err, visited)
+	}
+}
+
+func TestForEachDecodeError(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	insertAll(t, c, bson.M{"n": 1}, bson.M{"n": "two"}, bson.M{"n": 3})
+
+	items := NewCollection[testItem](c.Inner.Database(), c.Inner.Name(), zerolog.Nop())
+
+	var visited int
+	err := items.ForEach(ctx, nil, func(testItem) error {
+		visited++
+		return nil
+	}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err == nil || !strings.HasPrefix(err.Error(), strings.TrimSuffix(ErrMsgDecode, "%w")) {
+		t.Errorf("ForEach() error = %v, want a decode error", err)
+	}
+	if visited != 1 {
+		t.Errorf("ForEach() visited %d documents, want it to stop at the undecodable second", visited)
+	}
+}
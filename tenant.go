@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNoTenant = errors.New("tenant not found in context")
+
+type (
+	MultiDB struct {
+		Client      *mongo.Client
+		Log         zerolog.Logger
+		Resolve     func(tenant string) string
+		DefaultOpts []CollectionOption
+	}
+
+	tenantKey struct{}
+)
+
+func NewMultiDB(client *mongo.Client, log zerolog.Logger, resolve func(tenant string) string, opts ...CollectionOption) *MultiDB {
+	return &MultiDB{Client: client, Log: log, Resolve: resolve, DefaultOpts: opts}
+}
+
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && len(tenant) > 0
+}
+
+// TenantCollection returns the collection name in the database of the
+// tenant carried by ctx.
+func TenantCollection[T any](ctx context.Context, m *MultiDB, name string, opts ...CollectionOption) (*Collection[T], error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+
+	f := Factory{
+		DB:          m.Client.Database(m.Resolve(tenant)),
+		Log:         m.Log.With().Str("tenant", tenant).Logger(),
+		DefaultOpts: m.DefaultOpts,
+	}
+	return FactoryCollection[T](&f, name, opts...), nil
+}
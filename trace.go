@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+const (
+	ResultOK           = "ok"
+	ResultNotFound     = "not_found"
+	ResultDuplicateKey = "duplicate_key"
+	ResultTimeout      = "timeout"
+	ResultError        = "error"
+)
+
+type (
+	// Tracer starts a span per collection operation, named after it, e.g.
+	// "mongodb.FindOne". It keeps the package free of a tracing dependency;
+	// the trace/otel module implements it with OpenTelemetry.
+	Tracer interface {
+		Start(ctx context.Context, name string, collection string) (context.Context, Span)
+	}
+
+	// Span is ended once the operation is done. SetCount is called before
+	// End by operations returning or affecting a number of documents; a
+	// non-nil err passed to End should mark the span as failed.
+	Span interface {
+		SetCount(n int)
+		End(err error)
+	}
+
+	// Metrics observes every collection operation, e.g. to feed a
+	// mongodb_operations_total{op,result} counter, labeling results with
+	// OperationResult, and a mongodb_operation_duration_seconds{op}
+	// histogram. It must be safe for concurrent use.
+	Metrics interface {
+		ObserveOperation(op string, collection string, took time.Duration, err error)
+	}
+
+	// operation tracks a collection operation between begin and end.
+	operation struct {
+		name       string
+		collection string
+		start      time.Time
+		span       Span
+		metrics    Metrics
+	}
+)
+
+// WithTracer makes each operation run in a span started by t, a child of
+// the span active in the operation's context. Streaming operations such as
+// FindStream, Tail and Watch end their span once the stream ends.
+func WithTracer(t Tracer) CollectionOption {
+	return func(o *collectionOptions) {
+		o.Tracer = t
+	}
+}
+
+// WithMetrics reports each operation to m once it is done. UpsertStream is
+// reported as one BulkWrite per batch.
+func WithMetrics(m Metrics) CollectionOption {
+	return func(o *collectionOptions) {
+		o.Metrics = m
+	}
+}
+
+// OperationResult classifies err into a low-cardinality metric label.
+func OperationResult(err error) string {
+	switch {
+	case err == nil:
+		return ResultOK
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return ResultNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return ResultDuplicateKey
+	case mongo.IsTimeout(err):
+		return ResultTimeout
+	}
+	return ResultError
+}
+
+func (c *Collection[T]) begin(ctx context.Context, op string) (context.Context, *operation) {
+	o := &operation{name: op, collection: c.Inner.Name(), start: time.Now(), metrics: c.Metrics}
+	if c.Tracer != nil {
+		ctx, o.span = c.Tracer.Start(ctx, "mongodb."+op, c.Inner.Name())
+	}
+	return ctx, o
+}
+
+func (o *operation) count(n int) {
+	if o.span != nil {
+		o.span.SetCount(n)
+	}
+}
+
+func (o *operation) end(err error) {
+	if o.span != nil {
+		o.span.End(err)
+	}
+	if o.metrics != nil {
+		o.metrics.ObserveOperation(o.name, o.collection, time.Since(o.start), err)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/go-funcards/mongodb/trace/otel
+
+go 1.18
+
+require (
+	github.com/go-funcards/mongodb v0.0.0
+	github.com/rs/zerolog v1.27.0
+	go.mongodb.org/mongo-driver v1.10.0
+	go.opentelemetry.io/otel v1.8.0
+	go.opentelemetry.io/otel/sdk v1.8.0
+	go.opentelemetry.io/otel/trace v1.8.0
+)
+
+replace github.com/go-funcards/mongodb => ../..
@@ -0,0 +1,58 @@
+// Package otel traces collection operations with OpenTelemetry. It lives in
+// a module of its own so the mongodb module stays free of the SDK.
+package otel
+
+import (
+	"context"
+	"github.com/go-funcards/mongodb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+const instrumentationName = "github.com/go-funcards/mongodb"
+
+type (
+	// Tracer implements mongodb.Tracer with client spans carrying the
+	// db.system, db.operation and db.mongodb.collection attributes.
+	Tracer struct {
+		tracer trace.Tracer
+	}
+
+	span struct {
+		inner trace.Span
+	}
+)
+
+var _ mongodb.Tracer = (*Tracer)(nil)
+
+// New returns a Tracer whose spans come from tp, e.g.
+// otel.GetTracerProvider().
+func New(tp trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, collection string) (context.Context, mongodb.Span) {
+	ctx, s := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.operation", strings.TrimPrefix(name, "mongodb.")),
+			attribute.String("db.mongodb.collection", collection),
+		),
+	)
+	return ctx, span{inner: s}
+}
+
+func (s span) SetCount(n int) {
+	s.inner.SetAttributes(attribute.Int("db.mongodb.count", n))
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.inner.RecordError(err)
+		s.inner.SetStatus(codes.Error, err.Error())
+	}
+	s.inner.End()
+}
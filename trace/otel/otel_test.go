@@ -0,0 +1,94 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"github.com/go-funcards/mongodb"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func newTracer() (*Tracer, *tracetest.InMemoryExporter) {
+	exp := tracetest.NewInMemoryExporter()
+	return New(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))), exp
+}
+
+func attr(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSpan(t *testing.T) {
+	tracer, exp := newTracer()
+
+	_, s := tracer.Start(context.Background(), "mongodb.FindIDs", "items")
+	s.SetCount(3)
+	s.End(nil)
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	if got.Name != "mongodb.FindIDs" || got.SpanKind != trace.SpanKindClient {
+		t.Errorf("span = %s of kind %v, want a client mongodb.FindIDs", got.Name, got.SpanKind)
+	}
+	if v := attr(got, "db.operation").AsString(); v != "FindIDs" {
+		t.Errorf("db.operation = %q, want FindIDs", v)
+	}
+	if v := attr(got, "db.mongodb.collection").AsString(); v != "items" {
+		t.Errorf("db.mongodb.collection = %q, want items", v)
+	}
+	if v := attr(got, "db.mongodb.count").AsInt64(); v != 3 {
+		t.Errorf("db.mongodb.count = %d, want 3", v)
+	}
+	if got.Status.Code != codes.Unset {
+		t.Errorf("status = %v, want unset", got.Status.Code)
+	}
+}
+
+func TestSpanError(t *testing.T) {
+	tracer, exp := newTracer()
+
+	_, s := tracer.Start(context.Background(), "mongodb.FindOne", "items")
+	s.End(errors.New("boom"))
+
+	got := exp.GetSpans()[0]
+	if got.Status.Code != codes.Error || got.Status.Description != "boom" {
+		t.Errorf("status = %+v, want an error with boom", got.Status)
+	}
+	if len(got.Events) != 1 || got.Events[0].Name != "exception" {
+		t.Errorf("events = %+v, want the recorded error", got.Events)
+	}
+}
+
+func TestCollectionSpans(t *testing.T) {
+	tracer, exp := newTracer()
+
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://127.0.0.1:1/?serverSelectionTimeoutMS=100"))
+	if err != nil {
+		t.Fatalf("mongo.NewClient() error = %v", err)
+	}
+	c := mongodb.NewCollection[struct{}](client.Database("test"), "items", zerolog.Nop(), mongodb.WithTracer(tracer))
+
+	// an invalid id fails before the client is needed
+	if _, err = c.FindOne(context.Background(), "bad"); err == nil {
+		t.Fatal("FindOne() error = nil, want an invalid id error")
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "mongodb.FindOne" || spans[0].Status.Code != codes.Error {
+		t.Errorf("spans = %+v, want a failed mongodb.FindOne", spans)
+	}
+}
@@ -0,0 +1,221 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	fakeTracer struct {
+		mu    sync.Mutex
+		spans []*fakeSpan
+	}
+
+	fakeSpan struct {
+		name       string
+		collection string
+		count      int
+		ended      bool
+		err        error
+	}
+)
+
+func (t *fakeTracer) Start(ctx context.Context, name string, collection string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &fakeSpan{name: name, collection: collection, count: -1}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (s *fakeSpan) SetCount(n int) {
+	s.count = n
+}
+
+func (s *fakeSpan) End(err error) {
+	s.ended, s.err = true, err
+}
+
+func (t *fakeTracer) last() *fakeSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.spans) == 0 {
+		return nil
+	}
+	return t.spans[len(t.spans)-1]
+}
+
+// failFast calls operations that fail before reaching the server, keyed by
+// the operation name they report.
+func failFast(c *Collection[bson.M]) map[string]func(ctx context.Context) {
+	return map[string]func(ctx context.Context){
+		"FindOne":             func(ctx context.Context) { _, _ = c.FindOne(ctx, "bad") },
+		"FindByIDsMap":        func(ctx context.Context) { _, _ = c.FindByIDsMap(ctx, []string{"bad"}) },
+		"FindIDs":             func(ctx context.Context) { _, _ = c.FindIDs(ctx, "bad") },
+		"DeleteManyReturning": func(ctx context.Context) { _, _ = c.DeleteManyReturning(ctx, "bad") },
+		"EstimateMatches":     func(ctx context.Context) { _, _ = c.EstimateMatches(ctx, "bad") },
+		"CompareAndSet":       func(ctx context.Context) { _, _ = c.CompareAndSet(ctx, "bad", "n", 1, bson.M{}) },
+		"CountByTimeBucket":   func(ctx context.Context) { _, _ = c.CountByTimeBucket(ctx, "at", 0, nil) },
+		"TopNPerGroup":        func(ctx context.Context) { _, _ = c.TopNPerGroup(ctx, "g", "s", 1, "bad") },
+		"ForEach":             func(ctx context.Context) { _ = c.ForEach(ctx, "bad", func(bson.M) error { return nil }) },
+		"Scan":                func(ctx context.Context) { _ = c.Scan(ctx, "bad", func(bson.M) error { return nil }) },
+		"FindJSONReader":      func(ctx context.Context) { _, _ = c.FindJSONReader(ctx, "bad") },
+		"Explain":             func(ctx context.Context) { _, _ = c.Explain(ctx, "bad", ExplainQueryPlanner) },
+		"RequireIndex":        func(ctx context.Context) { _ = c.RequireIndex(ctx, "bad", nil) },
+		"OpenChangeStream":    func(ctx context.Context) { _, _ = c.OpenChangeStream(ctx, nil) },
+		"EncryptField":        func(ctx context.Context) { _, _ = c.EncryptField(ctx, "v", primitive.Binary{}, "") },
+		"DecryptField":        func(ctx context.Context) { _, _ = c.DecryptField(ctx, primitive.Binary{}) },
+		"Ping":                func(ctx context.Context) { _ = c.Ping(ctx) },
+		"WithTransaction": func(ctx context.Context) {
+			_ = c.WithTransaction(ctx, func(mongo.SessionContext) error { return errors.New("boom") })
+		},
+		"FindStream": func(ctx context.Context) {
+			_, errs := c.FindStream(ctx, "bad")
+			<-errs
+		},
+	}
+}
+
+func TestTracerSpans(t *testing.T) {
+	tracer := &fakeTracer{}
+	c := offlineCollection[bson.M](t, WithTracer(tracer))
+
+	for op, call := range failFast(c) {
+		call(context.Background())
+
+		span := tracer.last()
+		if span == nil || span.name != "mongodb."+op {
+			t.Errorf("%s: span = %+v, want mongodb.%s", op, span, op)
+			continue
+		}
+		if span.collection != "items" {
+			t.Errorf("%s: span collection = %q, want items", op, span.collection)
+		}
+		if !span.ended || span.err == nil {
+			t.Errorf("%s: span ended = %v with err = %v, want ended with an error", op, span.ended, span.err)
+		}
+	}
+}
+
+func TestTracerSpanCount(t *testing.T) {
+	ctx := context.Background()
+	tracer := &fakeTracer{}
+	c := testCollection[bson.M](t, WithTracer(tracer))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.InsertOne(ctx, bson.M{"n": i}); err != nil {
+			t.Fatalf("InsertOne() error = %v", err)
+		}
+	}
+
+	if _, err := c.FindIDs(ctx, nil); err != nil {
+		t.Fatalf("FindIDs() error = %v", err)
+	}
+	span := tracer.last()
+	if span.name != "mongodb.FindIDs" || !span.ended || span.err != nil || span.count != 3 {
+		t.Errorf("span = %+v, want a successful mongodb.FindIDs with count 3", span)
+	}
+}
+
+type (
+	fakeMetrics struct {
+		mu  sync.Mutex
+		ops []observedOp
+	}
+
+	observedOp struct {
+		op         string
+		collection string
+		err        error
+	}
+)
+
+func (m *fakeMetrics) ObserveOperation(op string, collection string, took time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ops = append(m.ops, observedOp{op: op, collection: collection, err: err})
+}
+
+func (m *fakeMetrics) last() (observedOp, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.ops) == 0 {
+		return observedOp{}, false
+	}
+	return m.ops[len(m.ops)-1], true
+}
+
+func TestMetricsOperations(t *testing.T) {
+	metrics := &fakeMetrics{}
+	c := offlineCollection[bson.M](t, WithMetrics(metrics))
+
+	for op, call := range failFast(c) {
+		call(context.Background())
+
+		got, ok := metrics.last()
+		if !ok || got.op != op || got.collection != "items" {
+			t.Errorf("%s: observed %+v, want %s on items", op, got, op)
+			continue
+		}
+		if got.err == nil || OperationResult(got.err) != ResultError {
+			t.Errorf("%s: observed err = %v, want a %s result", op, got.err, ResultError)
+		}
+	}
+}
+
+func TestMetricsUpsertStream(t *testing.T) {
+	ctx := context.Background()
+	metrics := &fakeMetrics{}
+	c := testCollection[bson.M](t, WithMetrics(metrics))
+
+	in := make(chan bson.M, 3)
+	for _, key := range []string{"a", "b", "c"} {
+		in <- bson.M{"key": key}
+	}
+	close(in)
+	for err := range c.UpsertStream(ctx, in, "key", 2, 0) {
+		t.Fatalf("UpsertStream() error = %v", err)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	var batches int
+	for _, o := range metrics.ops {
+		if o.op == "BulkWrite" && o.err == nil {
+			batches++
+		}
+	}
+	if batches != 2 {
+		t.Errorf("observed %d BulkWrite operations, want 2", batches)
+	}
+}
+
+func TestOperationResult(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ResultOK},
+		{fmt.Errorf("find: %w", mongo.ErrNoDocuments), ResultNotFound},
+		{mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, ResultDuplicateKey},
+		{context.DeadlineExceeded, ResultTimeout},
+		{errors.New("boom"), ResultError},
+	}
+	for _, tt := range tests {
+		if got := OperationResult(tt.err); got != tt.want {
+			t.Errorf("OperationResult(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// field update
+	inc      = operator("$inc")
+	mul      = operator("$mul")
+	fieldMin = operator("$min")
+	fieldMax = operator("$max")
+)
+
+func Inc(name string, by any) bson.M {
+	return bson.M{string(inc): bson.M{name: by}}
+}
+
+func Mul(name string, by any) bson.M {
+	return bson.M{string(mul): bson.M{name: by}}
+}
+
+func FieldMin(name string, value any) bson.M {
+	return bson.M{string(fieldMin): bson.M{name: value}}
+}
+
+func FieldMax(name string, value any) bson.M {
+	return bson.M{string(fieldMax): bson.M{name: value}}
+}
+
+// MergeUpdates combines update documents, merging fields that share an
+// operator, e.g. Inc("a", 1) and Inc("b", 2) into one $inc.
+func MergeUpdates(updates ...bson.M) bson.M {
+	result := bson.M{}
+	for _, update := range updates {
+		for op, value := range update {
+			fields, ok := value.(bson.M)
+			if !ok {
+				result[op] = value
+				continue
+			}
+			merged, ok := result[op].(bson.M)
+			if !ok {
+				merged = bson.M{}
+				result[op] = merged
+			}
+			for k, v := range fields {
+				merged[k] = v
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"reflect"
+	"testing"
+)
+
+func TestMergeUpdates(t *testing.T) {
+	set := bson.M{"$set": bson.M{"name": "alice"}}
+	got := MergeUpdates(Inc("a", 1), Inc("b", 2), FieldMax("c", 3), set, Mul("a", 2))
+	want := bson.M{
+		"$inc": bson.M{"a": 1, "b": 2},
+		"$max": bson.M{"c": 3},
+		"$set": bson.M{"name": "alice"},
+		"$mul": bson.M{"a": 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeUpdates() = %v, want %v", got, want)
+	}
+
+	// the inputs are left untouched
+	if !reflect.DeepEqual(set, bson.M{"$set": bson.M{"name": "alice"}}) {
+		t.Errorf("MergeUpdates() modified its input: %v", set)
+	}
+}
+
+func TestMergeUpdatesLaterWins(t *testing.T) {
+	got := MergeUpdates(FieldMin("a", 1), FieldMin("a", 5))
+	if want := (bson.M{"$min": bson.M{"a": 5}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeUpdates() = %v, want %v", got, want)
+	}
+}
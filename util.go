@@ -2,10 +2,15 @@ package mongodb
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"reflect"
+	"time"
 )
 
 const (
@@ -19,8 +24,50 @@ func FindOptions(index uint64, size uint32) *options.FindOptions {
 	return options.Find().SetSkip(int64(index)).SetLimit(int64(size))
 }
 
+// SortAsc sorts by fields ascending, the first field taking precedence.
+func SortAsc(fields ...string) *options.FindOptions {
+	return options.Find().SetSort(Asc(fields...))
+}
+
+// SortDesc sorts by fields descending, the first field taking precedence.
+func SortDesc(fields ...string) *options.FindOptions {
+	return options.Find().SetSort(Desc(fields...))
+}
+
+func Asc(fields ...string) bson.D {
+	return sortSpec(fields, 1)
+}
+
+func Desc(fields ...string) bson.D {
+	return sortSpec(fields, -1)
+}
+
+// Sort combines specs into a compound sort in the given order, e.g.
+//
+//	FindOptions(0, 20).SetSort(Sort(Desc("createdAt"), Asc("name")))
+func Sort(specs ...bson.D) bson.D {
+	var sort bson.D
+	for _, spec := range specs {
+		sort = append(sort, spec...)
+	}
+	return sort
+}
+
+func sortSpec(fields []string, direction int) bson.D {
+	spec := make(bson.D, len(fields))
+	for i, field := range fields {
+		spec[i] = bson.E{Key: field, Value: direction}
+	}
+	return spec
+}
+
+// DecodeOne returns mongo.ErrNoDocuments unwrapped, since not finding a
+// document is a result rather than a failed query.
 func DecodeOne[T any](r *mongo.SingleResult) (doc T, err error) {
 	if r.Err() != nil {
+		if errors.Is(r.Err(), mongo.ErrNoDocuments) {
+			return doc, mongo.ErrNoDocuments
+		}
 		return doc, fmt.Errorf(ErrMsgQuery, r.Err())
 	}
 	if err = r.Decode(&doc); err != nil {
@@ -39,6 +86,32 @@ func DecodeAll[T any](ctx context.Context, cur *mongo.Cursor) (docs []T, err err
 	return docs, nil
 }
 
+// DiffDocuments returns the top-level fields that differ between before and
+// after as field -> {old, new}, using the same bson field names as storage.
+func DiffDocuments[T any](before, after T) (bson.M, error) {
+	b, err := ToBson(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := ToBson(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := bson.M{}
+	for k, old := range b {
+		if v, ok := a[k]; !ok || !reflect.DeepEqual(old, v) {
+			diff[k] = bson.M{"old": old, "new": v}
+		}
+	}
+	for k, v := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = bson.M{"old": nil, "new": v}
+		}
+	}
+	return diff, nil
+}
+
 func ToBson(doc any) (bson.M, error) {
 	data, err := bson.Marshal(doc)
 	if err != nil {
@@ -51,3 +124,53 @@ func ToBson(doc any) (bson.M, error) {
 	}
 	return m, nil
 }
+
+// ObjectIDFromTime returns an ObjectID holding only the timestamp of t, so it
+// sorts before every ObjectID generated within or after the same second.
+func ObjectIDFromTime(t time.Time) (id primitive.ObjectID) {
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()))
+	return id
+}
+
+func idString(id any) string {
+	if oid, ok := id.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return fmt.Sprint(id)
+}
+
+func rawIDString(id bson.RawValue) string {
+	if oid, ok := id.ObjectIDOK(); ok {
+		return oid.Hex()
+	}
+	if s, ok := id.StringValueOK(); ok {
+		return s
+	}
+	return id.String()
+}
+
+// omitNilID marshals doc and drops a zero ObjectID _id, so the driver
+// generates a fresh one instead of inserting the same zero id every time.
+func omitNilID(doc any) (any, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgMarshal, err)
+	}
+
+	if id, ok := bson.Raw(data).Lookup("_id").ObjectIDOK(); !ok || !id.IsZero() {
+		return bson.Raw(data), nil
+	}
+
+	var d bson.D
+	if err = bson.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf(ErrMsgUnmarshal, err)
+	}
+
+	result := make(bson.D, 0, len(d)-1)
+	for _, e := range d {
+		if e.Key != "_id" {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+	"testing"
+)
+
+func TestSortOrder(t *testing.T) {
+	got := Sort(Desc("createdAt", "priority"), Asc("name"))
+	want := bson.D{
+		{Key: "createdAt", Value: -1},
+		{Key: "priority", Value: -1},
+		{Key: "name", Value: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() = %v, want %v", got, want)
+	}
+
+	if sort := SortAsc("a", "b").Sort; !reflect.DeepEqual(sort, Asc("a", "b")) {
+		t.Errorf("SortAsc().Sort = %v, want %v", sort, Asc("a", "b"))
+	}
+	if sort := SortDesc("a", "b").Sort; !reflect.DeepEqual(sort, Desc("a", "b")) {
+		t.Errorf("SortDesc().Sort = %v, want %v", sort, Desc("a", "b"))
+	}
+}
+
+func TestDiffDocuments(t *testing.T) {
+	type card struct {
+		Title string   `bson:"title"`
+		Done  bool     `bson:"done"`
+		Tags  []string `bson:"tags,omitempty"`
+	}
+
+	diff, err := DiffDocuments(card{Title: "a", Tags: []string{"x"}}, card{Title: "b"})
+	if err != nil {
+		t.Fatalf("DiffDocuments() error = %v", err)
+	}
+	want := bson.M{
+		"title": bson.M{"old": "a", "new": "b"},
+		"tags":  bson.M{"old": bson.A{"x"}, "new": nil},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffDocuments() = %v, want %v", diff, want)
+	}
+
+	if diff, err = DiffDocuments(card{Title: "a"}, card{Title: "a"}); err != nil || len(diff) != 0 {
+		t.Errorf("DiffDocuments() = %v, %v, want no differences", diff, err)
+	}
+}
+
+func TestDecodeOneNotFound(t *testing.T) {
+	_, err := DecodeOne[bson.M](mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil))
+	if err != mongo.ErrNoDocuments {
+		t.Errorf("DecodeOne() error = %v, want mongo.ErrNoDocuments unwrapped", err)
+	}
+
+	doc, err := DecodeOne[bson.M](mongo.NewSingleResultFromDocument(bson.M{"n": 1}, nil, nil))
+	if err != nil || doc["n"] != int32(1) {
+		t.Errorf("DecodeOne() = %v, %v, want the document", doc, err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	ctx := context.Background()
+	c := testCollection[bson.M](t)
+	id := primitive.NewObjectID()
+
+	if _, err := c.FindOne(ctx, id); err != mongo.ErrNoDocuments {
+		t.Errorf("FindOne() error = %v, want mongo.ErrNoDocuments", err)
+	}
+	if err := c.UpdateOne(ctx, id, bson.M{"$set": bson.M{"n": 1}}); err != mongo.ErrNoDocuments {
+		t.Errorf("UpdateOne() error = %v, want mongo.ErrNoDocuments", err)
+	}
+	if err := c.DeleteOne(ctx, id); err != mongo.ErrNoDocuments {
+		t.Errorf("DeleteOne() error = %v, want mongo.ErrNoDocuments", err)
+	}
+}
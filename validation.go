@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const documentValidationFailureCode = 121
+
+type (
+	// ValidationFailure describes why a document was rejected by the
+	// collection's validator. Violations are only reported by servers that
+	// explain validation errors (5.0+); Details holds the raw explanation.
+	ValidationFailure struct {
+		DocumentID any
+		Violations []Violation
+		Details    bson.Raw
+	}
+
+	// Violation is a validator rule a field didn't satisfy. Field is a dotted
+	// path, empty for rules on the whole document.
+	Violation struct {
+		Field  string
+		Rule   string
+		Reason string
+	}
+
+	schemaRule struct {
+		OperatorName            string           `bson:"operatorName"`
+		Reason                  string           `bson:"reason"`
+		PropertiesNotSatisfied  []schemaProperty `bson:"propertiesNotSatisfied"`
+		MissingProperties       []string         `bson:"missingProperties"`
+		AdditionalProperties    []string         `bson:"additionalProperties"`
+		SchemaRulesNotSatisfied []schemaRule     `bson:"schemaRulesNotSatisfied"`
+	}
+
+	schemaProperty struct {
+		PropertyName string       `bson:"propertyName"`
+		Details      []schemaRule `bson:"details"`
+	}
+)
+
+// ParseValidationError extracts the failed validator rules from a document
+// validation error returned by any write method.
+func ParseValidationError(err error) (*ValidationFailure, bool) {
+	var (
+		details bson.Raw
+		found   bool
+		we      mongo.WriteException
+		bwe     mongo.BulkWriteException
+		ce      mongo.CommandError
+	)
+
+	switch {
+	case errors.As(err, &we):
+		for _, e := range we.WriteErrors {
+			if e.Code == documentValidationFailureCode {
+				details, found = e.Details, true
+				break
+			}
+		}
+	case errors.As(err, &bwe):
+		for _, e := range bwe.WriteErrors {
+			if e.Code == documentValidationFailureCode {
+				details, found = e.Details, true
+				break
+			}
+		}
+	case errors.As(err, &ce):
+		if ce.Code == documentValidationFailureCode {
+			details, _ = ce.Raw.Lookup("errInfo").DocumentOK()
+			found = true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	failure := &ValidationFailure{Details: details}
+	if len(details) == 0 {
+		return failure, true
+	}
+
+	var info struct {
+		FailingDocumentID any        `bson:"failingDocumentId"`
+		Details           schemaRule `bson:"details"`
+	}
+	if bson.Unmarshal(details, &info) == nil {
+		failure.DocumentID = info.FailingDocumentID
+		failure.Violations = violations("", info.Details)
+	}
+
+	return failure, true
+}
+
+func violations(path string, rule schemaRule) []Violation {
+	var result []Violation
+
+	for _, r := range rule.SchemaRulesNotSatisfied {
+		result = append(result, violations(path, r)...)
+	}
+	for _, p := range rule.PropertiesNotSatisfied {
+		for _, r := range p.Details {
+			result = append(result, violations(joinPath(path, p.PropertyName), r)...)
+		}
+	}
+	for _, name := range rule.MissingProperties {
+		result = append(result, Violation{Field: joinPath(path, name), Rule: "required", Reason: "missing"})
+	}
+	for _, name := range rule.AdditionalProperties {
+		result = append(result, Violation{Field: joinPath(path, name), Rule: "additionalProperties", Reason: "not allowed"})
+	}
+
+	// a rule without nested failures is itself the violation
+	if len(result) == 0 {
+		result = append(result, Violation{Field: path, Rule: rule.OperatorName, Reason: rule.Reason})
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+	"testing"
+)
+
+func TestViolations(t *testing.T) {
+	rule := schemaRule{
+		OperatorName: "$jsonSchema",
+		SchemaRulesNotSatisfied: []schemaRule{
+			{
+				OperatorName: "properties",
+				PropertiesNotSatisfied: []schemaProperty{
+					{PropertyName: "age", Details: []schemaRule{{OperatorName: "minimum", Reason: "comparison failed"}}},
+					{PropertyName: "address", Details: []schemaRule{{OperatorName: "required", MissingProperties: []string{"city"}}}},
+				},
+			},
+			{OperatorName: "additionalProperties", AdditionalProperties: []string{"extra"}},
+		},
+	}
+
+	want := []Violation{
+		{Field: "age", Rule: "minimum", Reason: "comparison failed"},
+		{Field: "address.city", Rule: "required", Reason: "missing"},
+		{Field: "extra", Rule: "additionalProperties", Reason: "not allowed"},
+	}
+	if got := violations("", rule); !reflect.DeepEqual(got, want) {
+		t.Errorf("violations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseValidationError(t *testing.T) {
+	details, err := bson.Marshal(bson.M{
+		"failingDocumentId": "doc1",
+		"details": bson.M{
+			"operatorName": "$jsonSchema",
+			"schemaRulesNotSatisfied": bson.A{bson.M{
+				"operatorName":      "required",
+				"missingProperties": bson.A{"name"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	failure, ok := ParseValidationError(mongo.WriteException{WriteErrors: mongo.WriteErrors{
+		{Code: documentValidationFailureCode, Details: details},
+	}})
+	if !ok {
+		t.Fatal("ParseValidationError() found no validation failure")
+	}
+	if failure.DocumentID != "doc1" {
+		t.Errorf("DocumentID = %v, want doc1", failure.DocumentID)
+	}
+	if want := []Violation{{Field: "name", Rule: "required", Reason: "missing"}}; !reflect.DeepEqual(failure.Violations, want) {
+		t.Errorf("Violations = %+v, want %+v", failure.Violations, want)
+	}
+
+	if _, ok = ParseValidationError(mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}); ok {
+		t.Error("ParseValidationError() found a failure in a duplicate key error")
+	}
+}
@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type ChangeEvent[T any] struct {
+	ID            bson.Raw            `bson:"_id"`
+	OperationType string              `bson:"operationType"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+	DocumentKey   bson.M              `bson:"documentKey"`
+	FullDocument  T                   `bson:"fullDocument"`
+}
+
+// ChangeStream is a typed change stream. Its ResumeToken stays readable
+// after Close, so consumers can persist it at shutdown and pass it to
+// options.ChangeStream().SetResumeAfter on restart.
+type ChangeStream[T any] struct {
+	inner *mongo.ChangeStream
+}
+
+func (c *Collection[T]) OpenChangeStream(ctx context.Context, pipeline any, opts ...*options.ChangeStreamOptions) (_ *ChangeStream[T], err error) {
+	ctx, o := c.begin(ctx, "OpenChangeStream")
+	defer func() { o.end(err) }()
+
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+
+	if comment, ok := c.comment(ctx); ok {
+		opts = append([]*options.ChangeStreamOptions{options.ChangeStream().SetComment(comment)}, opts...)
+	}
+
+	cs, err := c.Inner.Watch(ctx, pipeline, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMsgQuery, err)
+	}
+
+	c.debug(ctx).Msg("change stream opened")
+
+	return &ChangeStream[T]{inner: cs}, nil
+}
+
+func (c *Collection[T]) Watch(ctx context.Context, pipeline any, fn func(ChangeEvent[T]) error, opts ...*options.ChangeStreamOptions) (err error) {
+	ctx, o := c.begin(ctx, "Watch")
+	defer func() { o.end(err) }()
+
+	cs, err := c.OpenChangeStream(ctx, pipeline, opts...)
+	if err != nil {
+		return err
+	}
+	defer cs.Close(context.Background())
+
+	for cs.Next(ctx) {
+		event, err := cs.Event()
+		if err != nil {
+			return err
+		}
+		if err = fn(event); err != nil {
+			return err
+		}
+	}
+	return cs.Err()
+}
+
+// WatchSince starts the change stream at the cluster time matching since.
+// The oplog must still cover that time, otherwise the server rejects it.
+func (c *Collection[T]) WatchSince(ctx context.Context, since time.Time, fn func(ChangeEvent[T]) error, opts ...*options.ChangeStreamOptions) error {
+	opts = append([]*options.ChangeStreamOptions{
+		options.ChangeStream().SetStartAtOperationTime(&primitive.Timestamp{T: uint32(since.Unix())}),
+	}, opts...)
+	return c.Watch(ctx, nil, fn, opts...)
+}
+
+// Tail follows a capped collection like tail -f, calling fn for every new
+// document until ctx is done. It returns early if the server kills the
+// cursor, which also happens when the collection is empty at start.
+func (c *Collection[T]) Tail(ctx context.Context, filter any, fn func(T) error) (err error) {
+	ctx, o := c.begin(ctx, "Tail")
+	defer func() { o.end(err) }()
+
+	if filter, err = NormalizeFilter(filter); err != nil {
+		return err
+	}
+
+	cur, err := c.Inner.Find(ctx, filter, c.findOptions(ctx, []*options.FindOptions{options.Find().SetCursorType(options.TailableAwait)})...)
+	if err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	defer cur.Close(context.Background())
+
+	c.debug(ctx).Msg("tailable cursor opened")
+
+	for cur.Next(ctx) {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return fmt.Errorf(ErrMsgDecode, err)
+		}
+		if err = fn(doc); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err = cur.Err(); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	return nil
+}
+
+func (s *ChangeStream[T]) Next(ctx context.Context) bool {
+	return s.inner.Next(ctx)
+}
+
+func (s *ChangeStream[T]) Event() (event ChangeEvent[T], err error) {
+	if err = s.inner.Decode(&event); err != nil {
+		return event, fmt.Errorf(ErrMsgDecode, err)
+	}
+	return event, nil
+}
+
+func (s *ChangeStream[T]) ResumeToken() bson.Raw {
+	return s.inner.ResumeToken()
+}
+
+func (s *ChangeStream[T]) Err() error {
+	if err := s.inner.Err(); err != nil {
+		return fmt.Errorf(ErrMsgQuery, err)
+	}
+	return nil
+}
+
+func (s *ChangeStream[T]) Close(ctx context.Context) error {
+	return s.inner.Close(ctx)
+}